@@ -0,0 +1,197 @@
+package orale
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// convertToInt converts a loaded value into an int64 that fits within the
+// given bit size. Strings are parsed using Go integer literal syntax so hex
+// (0x), octal (0o), binary (0b) and underscore separated values are supported.
+// Strings in exponent form such as 1e6 are accepted if they describe a whole
+// number. Unsigned and float values are accepted if they can be represented
+// without loss.
+func convertToInt(value any, bitSize int) (int64, error) {
+	min := int64(-1) << (bitSize - 1)
+	max := -(min + 1)
+
+	switch val := value.(type) {
+	case int64:
+		if val < min || val > max {
+			return 0, fmt.Errorf("%d overflows a %d bit integer", val, bitSize)
+		}
+		return val, nil
+	case uint64:
+		if val > uint64(max) {
+			return 0, fmt.Errorf("%d overflows a %d bit integer", val, bitSize)
+		}
+		return int64(val), nil
+	case float64:
+		if val != math.Trunc(val) || math.IsInf(val, 0) {
+			return 0, fmt.Errorf("%v is not a whole number", val)
+		}
+		if val < float64(min) || val >= -float64(min) {
+			return 0, fmt.Errorf("%v overflows a %d bit integer", val, bitSize)
+		}
+		return int64(val), nil
+	case string:
+		return parseInt(val, bitSize)
+	}
+	return 0, newUnsupportedValueTypeError(value)
+}
+
+// convertToUint converts a loaded value into an uint64 that fits within the
+// given bit size. It follows the same rules as convertToInt.
+func convertToUint(value any, bitSize int) (uint64, error) {
+	max := uint64(math.MaxUint64) >> (64 - bitSize)
+
+	switch val := value.(type) {
+	case int64:
+		if val < 0 {
+			return 0, fmt.Errorf("%d is negative", val)
+		}
+		if uint64(val) > max {
+			return 0, fmt.Errorf("%d overflows a %d bit unsigned integer", val, bitSize)
+		}
+		return uint64(val), nil
+	case uint64:
+		if val > max {
+			return 0, fmt.Errorf("%d overflows a %d bit unsigned integer", val, bitSize)
+		}
+		return val, nil
+	case float64:
+		if val != math.Trunc(val) || math.IsInf(val, 0) {
+			return 0, fmt.Errorf("%v is not a whole number", val)
+		}
+		if val < 0 {
+			return 0, fmt.Errorf("%v is negative", val)
+		}
+		if val >= math.Ldexp(1, bitSize) {
+			return 0, fmt.Errorf("%v overflows a %d bit unsigned integer", val, bitSize)
+		}
+		return uint64(val), nil
+	case string:
+		return parseUint(val, bitSize)
+	}
+	return 0, newUnsupportedValueTypeError(value)
+}
+
+// convertToFloat converts a loaded value into a float64 that fits within the
+// given bit size. Strings are parsed using Go float literal syntax. Integer
+// values are accepted if they can be represented exactly.
+func convertToFloat(value any, bitSize int) (float64, error) {
+	switch val := value.(type) {
+	case float64:
+		if bitSize == 32 && !math.IsInf(val, 0) && math.Abs(val) > math.MaxFloat32 {
+			return 0, fmt.Errorf("%v overflows a 32 bit float", val)
+		}
+		return val, nil
+	case int64:
+		floatValue := float64(val)
+		if bitSize == 32 {
+			floatValue = float64(float32(val))
+		}
+		if floatValue >= math.Ldexp(1, 63) || int64(floatValue) != val {
+			return 0, fmt.Errorf("%d cannot be represented exactly as a %d bit float", val, bitSize)
+		}
+		return floatValue, nil
+	case uint64:
+		floatValue := float64(val)
+		if bitSize == 32 {
+			floatValue = float64(float32(val))
+		}
+		if floatValue >= math.Ldexp(1, 64) || uint64(floatValue) != val {
+			return 0, fmt.Errorf("%d cannot be represented exactly as a %d bit float", val, bitSize)
+		}
+		return floatValue, nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(val), bitSize)
+	}
+	return 0, newUnsupportedValueTypeError(value)
+}
+
+// convertToBool converts a loaded value into a bool. Strings are parsed with
+// strconv.ParseBool after being lower cased.
+func convertToBool(value any) (bool, error) {
+	switch val := value.(type) {
+	case bool:
+		return val, nil
+	case string:
+		return strconv.ParseBool(strings.ToLower(strings.TrimSpace(val)))
+	}
+	return false, newUnsupportedValueTypeError(value)
+}
+
+// convertToString converts a loaded value into a string. Only string values
+// are accepted.
+func convertToString(value any) (string, error) {
+	if val, ok := value.(string); ok {
+		return val, nil
+	}
+	return "", newUnsupportedValueTypeError(value)
+}
+
+func parseInt(str string, bitSize int) (int64, error) {
+	str = strings.TrimSpace(str)
+	if !isExponentLiteral(str) {
+		return strconv.ParseInt(str, 0, bitSize)
+	}
+	floatValue, err := parseWholeFloat(str)
+	if err != nil {
+		return 0, err
+	}
+	min := -math.Ldexp(1, bitSize-1)
+	max := math.Ldexp(1, bitSize-1)
+	if floatValue < min || floatValue >= max {
+		return 0, fmt.Errorf("%q overflows a %d bit integer", str, bitSize)
+	}
+	return int64(floatValue), nil
+}
+
+func parseUint(str string, bitSize int) (uint64, error) {
+	str = strings.TrimSpace(str)
+	if !isExponentLiteral(str) {
+		return strconv.ParseUint(str, 0, bitSize)
+	}
+	floatValue, err := parseWholeFloat(str)
+	if err != nil {
+		return 0, err
+	}
+	if floatValue < 0 || floatValue >= math.Ldexp(1, bitSize) {
+		return 0, fmt.Errorf("%q overflows a %d bit unsigned integer", str, bitSize)
+	}
+	return uint64(floatValue), nil
+}
+
+func parseWholeFloat(str string) (float64, error) {
+	floatValue, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, err
+	}
+	if floatValue != math.Trunc(floatValue) || math.IsInf(floatValue, 0) {
+		return 0, fmt.Errorf("%q is not a whole number", str)
+	}
+	return floatValue, nil
+}
+
+// isExponentLiteral returns true if the string is a decimal number written in
+// exponent form, for example 1e6 or 2.5E3. Hex literals are excluded as e and
+// E are valid hex digits.
+func isExponentLiteral(str string) bool {
+	lowerStr := strings.ToLower(strings.TrimLeft(str, "+-"))
+	if strings.HasPrefix(lowerStr, "0x") {
+		return false
+	}
+	return strings.Contains(lowerStr, "e")
+}
+
+func newConversionError(path, source string, targetType reflect.Type, value any, err error) error {
+	return fmt.Errorf("cannot convert value %#v at path %s from %s to %s: %w", value, path, source, targetType, err)
+}
+
+func newUnsupportedValueTypeError(value any) error {
+	return fmt.Errorf("unexpected value of type %T", value)
+}
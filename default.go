@@ -0,0 +1,54 @@
+package orale
+
+import (
+	"reflect"
+	"strings"
+)
+
+// newDefaultLoader creates a loader holding only the default value given by a
+// field's `default` tag. Slice and array defaults are split on commas.
+func newDefaultLoader(path, defaultTag string, fieldType reflect.Type) *Loader {
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	if isSecretType(fieldType) {
+		fieldType = secretInnerType(fieldType)
+	}
+
+	value := []any{defaultTag}
+	if (fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array) && !fieldType.Implements(textUnmarshalerType) && !reflect.PointerTo(fieldType).Implements(textUnmarshalerType) {
+		value = []any{}
+		if defaultTag != "" {
+			for _, entry := range strings.Split(defaultTag, ",") {
+				value = append(value, strings.TrimSpace(entry))
+			}
+		}
+	}
+
+	return &Loader{
+		Sources: []Source{NewSource(defaultSourceName, map[string][]any{path: value})},
+	}
+}
+
+// hasPath returns true if any source provides a value at or beneath the
+// target path.
+func hasPath(l *Loader, targetPath string) bool {
+	return findPathSource(l, targetPath) != nil
+}
+
+// findPathSource returns the highest precedence source providing a value at or
+// beneath the target path, or nil if no source provides it.
+func findPathSource(l *Loader, targetPath string) Source {
+	for _, source := range l.sources() {
+		sourceValues := source.FlatValues()
+		if _, ok := sourceValues[targetPath]; ok {
+			return source
+		}
+		for sourcePath := range sourceValues {
+			if isSubPath(sourcePath, targetPath) {
+				return source
+			}
+		}
+	}
+	return nil
+}
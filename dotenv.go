@@ -0,0 +1,226 @@
+package orale
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// loadDotenvFiles loads the dotenv files found in each search path and its
+// parents. Alongside each dotenv file name such as .env, the file for each
+// profile such as .env.production is loaded. Profile files take precedence
+// over the base file in the same directory, with later profiles taking
+// precedence over earlier ones.
+func loadDotenvFiles(options *loadOptions) ([]*File, error) {
+	dotenvFiles := []*File{}
+	if len(options.dotenvFileNames) == 0 {
+		return dotenvFiles, nil
+	}
+
+	dotenvNames := []string{}
+	for i := len(options.profiles) - 1; i >= 0; i -= 1 {
+		for _, dotenvFileName := range options.dotenvFileNames {
+			dotenvNames = append(dotenvNames, dotenvFileName+"."+options.profiles[i])
+		}
+	}
+	dotenvNames = append(dotenvNames, options.dotenvFileNames...)
+
+	loadedFilePaths := map[string]bool{}
+	for _, searchPath := range options.searchPaths {
+		for _, dirPath := range searchPathDirs(searchPath, options.isSearchBoundary) {
+			for _, dotenvName := range dotenvNames {
+				dotenvFilePath := filepath.Join(dirPath, dotenvName)
+				if loadedFilePaths[dotenvFilePath] {
+					continue
+				}
+				loadedFilePaths[dotenvFilePath] = true
+
+				dotenvFile, err := loadDotenvFile(dotenvFilePath, options.envPrefix)
+				if err != nil {
+					switch {
+					case os.IsNotExist(err), os.IsPermission(err):
+						continue
+					default:
+						return nil, err
+					}
+				}
+				takeExplicitConfigPaths(options.configPathKey, map[string][]any{}, dotenvFile.Values)
+				takeProfiles(options.profileKey, map[string][]any{}, dotenvFile.Values)
+				if options.fileReferences {
+					fileReferences, err := resolveFileReferences(dotenvFile.Values, dirPath)
+					if err != nil {
+						return nil, fmt.Errorf("failed to load %s: %w", dotenvFilePath, err)
+					}
+					for targetPath, referencePath := range fileReferences {
+						dotenvFile.lines[targetPath] = dotenvFile.lines[referencePath]
+						dotenvFile.ownPaths[targetPath] = true
+					}
+				}
+				dotenvFiles = append(dotenvFiles, dotenvFile)
+			}
+		}
+	}
+
+	return dotenvFiles, nil
+}
+
+// loadDotenvFile loads a dotenv file. Its variables are mapped to paths in the
+// same way as environment variables, so only variables with the environment
+// variable prefix are kept.
+func loadDotenvFile(dotenvFilePath string, envVarPrefix string) (*File, error) {
+	fileBytes, err := os.ReadFile(dotenvFilePath)
+	if err != nil {
+		return nil, err
+	}
+	dotenvVariables, err := parseDotenv(string(fileBytes), envVarPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", dotenvFilePath, err)
+	}
+
+	envVariables := []string{}
+	lines := map[string]int{}
+	ownPaths := map[string]bool{}
+	for _, dotenvVariable := range dotenvVariables {
+		envVariables = append(envVariables, dotenvVariable.envVariable)
+		if key, _, ok := parseEnvironmentVariable(envVarPrefix, dotenvVariable.envVariable); ok {
+			lines[key] = dotenvVariable.line
+			ownPaths[key] = true
+		}
+	}
+	return &File{
+		Path:     dotenvFilePath,
+		Format:   FormatDotenv,
+		Values:   loadEnvironment(envVarPrefix, envVariables),
+		lines:    lines,
+		ownPaths: ownPaths,
+	}, nil
+}
+
+// dotenvVariable is a variable parsed from a dotenv file along with the line it
+// starts on.
+type dotenvVariable struct {
+	envVariable string
+	line        int
+}
+
+// parseDotenv parses the contents of a dotenv file into variables in the
+// KEY=value format returned by `os.Environ()`. Lines may start with export.
+// Comments start with # and run to the end of the line. Values may be single
+// quoted, in which case they are taken literally, or double quoted, in which
+// case \n, \r, \t, \", \\ and \$ are unescaped. Quoted values may span
+// several lines.
+//
+// Dotenv files are often shared with other tools, such as docker compose, so
+// malformed lines are only an error if they belong to a variable with the
+// environment variable prefix. Other malformed lines are skipped.
+func parseDotenv(contents string, variablePrefix string) ([]dotenvVariable, error) {
+	lines := strings.Split(strings.ReplaceAll(contents, "\r\n", "\n"), "\n")
+	isPrefixed := func(key string) bool {
+		return strings.HasPrefix(key, variablePrefix+"__")
+	}
+
+	dotenvVariables := []dotenvVariable{}
+	for i := 0; i < len(lines); i += 1 {
+		lineNumber := i + 1
+		line := strings.TrimLeft(lines[i], " \t")
+		if strings.TrimSpace(line) == "" || line[0] == '#' {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			line = strings.TrimLeft(rest, " \t")
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			if isPrefixed(key) {
+				return nil, fmt.Errorf("line %d: expected KEY=value", lineNumber)
+			}
+			continue
+		}
+		value = strings.TrimLeft(value, " \t")
+
+		if value != "" && (value[0] == '"' || value[0] == '\'') {
+			quote := value[0]
+			quotedValue := value[1:]
+			closeIndex := findClosingQuote(quotedValue, quote)
+			for closeIndex == -1 && i+1 < len(lines) {
+				i += 1
+				quotedValue += "\n" + lines[i]
+				closeIndex = findClosingQuote(quotedValue, quote)
+			}
+			if closeIndex == -1 {
+				if isPrefixed(key) {
+					return nil, fmt.Errorf("line %d: unterminated quoted value", lineNumber)
+				}
+				// Resume after the line the value started on, as the rest of
+				// the file may still hold valid variables.
+				i = lineNumber - 1
+				continue
+			}
+			if trailing := strings.TrimSpace(quotedValue[closeIndex+1:]); trailing != "" && trailing[0] != '#' {
+				if isPrefixed(key) {
+					return nil, fmt.Errorf("line %d: unexpected characters after quoted value", lineNumber)
+				}
+				continue
+			}
+			value = quotedValue[:closeIndex]
+			if quote == '"' {
+				value = unescapeDotenvValue(value)
+			}
+		} else {
+			for j := 0; j < len(value); j += 1 {
+				if value[j] == '#' && (j == 0 || value[j-1] == ' ' || value[j-1] == '\t') {
+					value = value[:j]
+					break
+				}
+			}
+			value = strings.TrimSpace(value)
+		}
+
+		dotenvVariables = append(dotenvVariables, dotenvVariable{envVariable: key + "=" + value, line: lineNumber})
+	}
+
+	return dotenvVariables, nil
+}
+
+// findClosingQuote returns the index of the quote closing a quoted value, or
+// -1 if there is none. Within double quotes a quote may be escaped with a
+// backslash.
+func findClosingQuote(quotedValue string, quote byte) int {
+	for i := 0; i < len(quotedValue); i += 1 {
+		switch {
+		case quote == '"' && quotedValue[i] == '\\':
+			i += 1
+		case quotedValue[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+func unescapeDotenvValue(value string) string {
+	unescaped := strings.Builder{}
+	for i := 0; i < len(value); i += 1 {
+		if value[i] != '\\' || i+1 == len(value) {
+			unescaped.WriteByte(value[i])
+			continue
+		}
+		i += 1
+		switch value[i] {
+		case 'n':
+			unescaped.WriteByte('\n')
+		case 'r':
+			unescaped.WriteByte('\r')
+		case 't':
+			unescaped.WriteByte('\t')
+		case '"', '\\', '$':
+			unescaped.WriteByte(value[i])
+		default:
+			unescaped.WriteByte('\\')
+			unescaped.WriteByte(value[i])
+		}
+	}
+	return unescaped.String()
+}
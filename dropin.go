@@ -0,0 +1,68 @@
+package orale
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// loadDropInFiles loads the fragments from the drop-in directories of the
+// given configuration file names. The drop-in directory of my-app.config.toml
+// is my-app.config.d. Fragments are loaded in lexical order, with each
+// fragment taking precedence over the ones before it, so they are returned in
+// reverse lexical order. Files in the drop-in directory without a known
+// configuration file extension are ignored.
+func loadDropInFiles(dirPath string, configNames []string, includes bool) ([]*File, error) {
+	dropInFiles := []*File{}
+	seenDropInDirs := map[string]bool{}
+	for _, configName := range configNames {
+		dropInDirName := strings.TrimSuffix(configName, filepath.Ext(configName)) + ".d"
+		if seenDropInDirs[dropInDirName] {
+			continue
+		}
+		seenDropInDirs[dropInDirName] = true
+
+		dropInDirPath := filepath.Join(dirPath, dropInDirName)
+		entries, err := os.ReadDir(dropInDirPath)
+		if err != nil {
+			switch {
+			case os.IsNotExist(err), os.IsPermission(err), errors.Is(err, syscall.ENOTDIR):
+				continue
+			default:
+				return nil, err
+			}
+		}
+
+		fragmentNames := []string{}
+		for _, entry := range entries {
+			if entry.IsDir() || !isConfigFileName(entry.Name()) {
+				continue
+			}
+			fragmentNames = append(fragmentNames, entry.Name())
+		}
+		sort.Strings(fragmentNames)
+
+		for i := len(fragmentNames) - 1; i >= 0; i -= 1 {
+			fragment, err := loadFile(filepath.Join(dropInDirPath, fragmentNames[i]), includes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load drop-in fragment: %w", err)
+			}
+			dropInFiles = append(dropInFiles, fragment)
+		}
+	}
+	return dropInFiles, nil
+}
+
+// isConfigFileName returns true if the file name has the extension of a
+// supported configuration file format.
+func isConfigFileName(fileName string) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".toml", ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
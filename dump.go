@@ -0,0 +1,308 @@
+package orale
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// DumpOption configures the output of Dump and Marshal.
+type DumpOption func(*dumpOptions)
+
+type dumpOptions struct {
+	sourceLoader *Loader
+}
+
+// WithSourceComments annotates each key with the source its value was taken
+// from in the given loader, for example `port = 9000 # --port (flags)`.
+// Only TOML output supports comments.
+func WithSourceComments(l *Loader) DumpOption {
+	return func(o *dumpOptions) {
+		o.sourceLoader = l
+	}
+}
+
+// Dump writes out the merged values of every source as TOML or JSON, with
+// each value, slice and table resolved from the sources with the same
+// precedence as Get. Values are written as loaded, so flag and environment
+// values are strings. Encrypted values and values read from secret files are
+// written as [REDACTED]. The loader does not know which fields of a
+// configuration struct are secrets, so to redact them too dump through
+// WithSecretsOf, or populate the struct and use Marshal. This is useful for
+// logging the effective configuration at startup.
+func (l *Loader) Dump(format Format, opts ...DumpOption) ([]byte, error) {
+	subtree, err := resolveSubtree(l, "", true)
+	if err != nil {
+		return nil, err
+	}
+	tree, ok := subtree.(map[string]any)
+	if !ok {
+		tree = map[string]any{}
+	}
+
+	options := &dumpOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return encodeDump(tree, format, options)
+}
+
+// Marshal writes out a configuration struct, such as one populated by Get, as
+// TOML or JSON. Keys are named by the `config` tags of the fields, so the
+// output can be loaded back in as a configuration file. Secret fields are
+// written as [REDACTED], durations as duration strings, and types
+// implementing encoding.TextMarshaler as text.
+func Marshal(cfg any, format Format, opts ...DumpOption) ([]byte, error) {
+	value, ok := marshalValue(reflect.ValueOf(cfg))
+	tree, isTable := value.(map[string]any)
+	if !ok || !isTable {
+		return nil, fmt.Errorf("cannot marshal %T, expected a struct or a map with string keys", cfg)
+	}
+
+	options := &dumpOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return encodeDump(tree, format, options)
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// marshalValue converts a value into the map[string]any, []any and scalar
+// values produced by the file decoders. The boolean result is false if the
+// value should be left out, as nil pointers are.
+func marshalValue(refVal reflect.Value) (any, bool) {
+	if !refVal.IsValid() {
+		return nil, false
+	}
+	refType := refVal.Type()
+	switch {
+	case isSecretType(refType):
+		return redactedText, true
+	case refType == timeType:
+		return refVal.Interface(), true
+	case refType == durationType:
+		return time.Duration(refVal.Int()).String(), true
+	case refType.Implements(textMarshalerType) && (refVal.Kind() != reflect.Ptr || !refVal.IsNil()):
+		text, err := refVal.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, false
+		}
+		return string(text), true
+	}
+
+	switch refVal.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if refVal.IsNil() {
+			return nil, false
+		}
+		return marshalValue(refVal.Elem())
+
+	case reflect.Struct:
+		table := map[string]any{}
+		for i := 0; i < refVal.NumField(); i += 1 {
+			field := refType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if value, ok := marshalValue(refVal.Field(i)); ok {
+				table[parseConfigTag(field).name] = value
+			}
+		}
+		return table, true
+
+	case reflect.Map:
+		if refType.Key().Kind() != reflect.String {
+			return nil, false
+		}
+		table := map[string]any{}
+		for _, key := range refVal.MapKeys() {
+			if value, ok := marshalValue(refVal.MapIndex(key)); ok {
+				table[key.String()] = value
+			}
+		}
+		return table, true
+
+	case reflect.Slice, reflect.Array:
+		if refVal.Kind() == reflect.Slice && refVal.IsNil() {
+			return nil, false
+		}
+		entries := []any{}
+		for i := 0; i < refVal.Len(); i += 1 {
+			if value, ok := marshalValue(refVal.Index(i)); ok {
+				entries = append(entries, value)
+			}
+		}
+		return entries, true
+
+	case reflect.String:
+		return refVal.String(), true
+	case reflect.Bool:
+		return refVal.Bool(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return refVal.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return refVal.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		return refVal.Float(), true
+	}
+	return nil, false
+}
+
+func encodeDump(tree map[string]any, format Format, options *dumpOptions) ([]byte, error) {
+	switch format {
+	case FormatTOML:
+		buf := &bytes.Buffer{}
+		if err := writeTOMLTable(buf, "", "", false, tree, options); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatJSON:
+		if options.sourceLoader != nil {
+			return nil, fmt.Errorf("source comments are not supported by %s", format)
+		}
+		jsonBytes, err := json.MarshalIndent(tree, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(jsonBytes, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unsupported dump format %s", format)
+	}
+}
+
+// writeTOMLTable writes a table's values followed by its sub tables and
+// arrays of tables. The path locates the table's values for source comments
+// and the header is the table's key as written in TOML.
+func writeTOMLTable(buf *bytes.Buffer, tablePath, header string, isArrayEntry bool, table map[string]any, options *dumpOptions) error {
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	valueKeys, tableKeys, arrayTableKeys := []string{}, []string{}, []string{}
+	for _, key := range keys {
+		switch value := table[key].(type) {
+		case map[string]any:
+			tableKeys = append(tableKeys, key)
+		case []any:
+			if isTOMLTableArray(value) {
+				arrayTableKeys = append(arrayTableKeys, key)
+			} else {
+				valueKeys = append(valueKeys, key)
+			}
+		default:
+			valueKeys = append(valueKeys, key)
+		}
+	}
+
+	if header != "" && (isArrayEntry || len(valueKeys) != 0 || (len(tableKeys) == 0 && len(arrayTableKeys) == 0)) {
+		if buf.Len() != 0 {
+			buf.WriteString("\n")
+		}
+		if isArrayEntry {
+			fmt.Fprintf(buf, "[[%s]]\n", header)
+		} else {
+			fmt.Fprintf(buf, "[%s]\n", header)
+		}
+	}
+
+	for _, key := range valueKeys {
+		encodedValue, err := encodeTOMLValue(table[key])
+		if err != nil {
+			return fmt.Errorf("cannot encode value at path %s: %w", joinPath(tablePath, key), err)
+		}
+		fmt.Fprintf(buf, "%s = %s", encodeTOMLKey(key), encodedValue)
+		if comment := sourceComment(options, joinPath(tablePath, key)); comment != "" {
+			fmt.Fprintf(buf, " # %s", comment)
+		}
+		buf.WriteString("\n")
+	}
+	for _, key := range tableKeys {
+		subTable := table[key].(map[string]any)
+		if err := writeTOMLTable(buf, joinPath(tablePath, key), joinTOMLHeader(header, key), false, subTable, options); err != nil {
+			return err
+		}
+	}
+	for _, key := range arrayTableKeys {
+		for i, entry := range table[key].([]any) {
+			entryPath := fmt.Sprintf("%s[%d]", joinPath(tablePath, key), i)
+			if err := writeTOMLTable(buf, entryPath, joinTOMLHeader(header, key), true, entry.(map[string]any), options); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isTOMLTableArray(entries []any) bool {
+	if len(entries) == 0 {
+		return false
+	}
+	for _, entry := range entries {
+		if _, ok := entry.(map[string]any); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeTOMLValue encodes a value with the toml encoder so strings, dates and
+// arrays are written exactly as the toml package expects to read them.
+func encodeTOMLValue(value any) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := toml.NewEncoder(buf).Encode(map[string]any{"v": value}); err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(strings.TrimSpace(buf.String()), "v = "), nil
+}
+
+var bareTOMLKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func encodeTOMLKey(key string) string {
+	if bareTOMLKeyPattern.MatchString(key) {
+		return key
+	}
+	quotedKey, err := encodeTOMLValue(key)
+	if err != nil {
+		return fmt.Sprintf("%q", key)
+	}
+	return quotedKey
+}
+
+func joinTOMLHeader(header, key string) string {
+	if header == "" {
+		return encodeTOMLKey(key)
+	}
+	return header + "." + encodeTOMLKey(key)
+}
+
+// sourceComment describes the source of the value at the path in the loader
+// given by WithSourceComments. Arrays provided as separate entries are
+// described by their first entry. Secrets are described by the source name
+// alone, in case a custom source locates its values by their contents.
+func sourceComment(options *dumpOptions, path string) string {
+	if options.sourceLoader == nil {
+		return ""
+	}
+	explanation := options.sourceLoader.Explain(path)
+	if explanation.Value == nil {
+		explanation = options.sourceLoader.Explain(path + "[0]")
+	}
+	if explanation.Value == nil {
+		return ""
+	}
+	if explanation.Redacted {
+		return explanation.Value.Source
+	}
+	return explanation.Value.describe()
+}
@@ -0,0 +1,237 @@
+package orale_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	orale "github.com/RobertWHurst/orale"
+)
+
+func TestDump(t *testing.T) {
+	t.Parallel()
+
+	newTestLoader := func() *orale.Loader {
+		return &orale.Loader{
+			FlagValues:        map[string][]any{"server.port": {"9000"}},
+			EnvironmentValues: map[string][]any{"database.password": {"hunter2"}},
+			ConfigurationFiles: []*orale.File{
+				{
+					Path: "/etc/test-app/test-app.config.toml",
+					Values: map[string][]any{
+						"server.port":      {int64(8080)},
+						"server.hosts[0]":  {"a"},
+						"server.hosts[1]":  {"b"},
+						"channels[0].name": {"one"},
+						"channels[1].name": {"two"},
+					},
+				},
+			},
+		}
+	}
+	type TestConfig struct {
+		Database struct {
+			Password orale.Secret[string] `config:"password"`
+		} `config:"database"`
+		Server struct {
+			Port  int      `config:"port"`
+			Hosts []string `config:"hosts"`
+		} `config:"server"`
+		Channels []struct {
+			Name string `config:"name"`
+		} `config:"channels"`
+		Timeout time.Duration `config:"timeout" default:"30s"`
+	}
+
+	t.Run("should dump the merged values as toml with secrets redacted", func(t *testing.T) {
+		t.Parallel()
+
+		conf := newTestLoader()
+		dump, err := conf.WithSecretsOf(TestConfig{}).Dump(orale.FormatTOML, orale.WithSourceComments(conf))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedDump := strings.Join([]string{
+			`[database]`,
+			`password = "[REDACTED]" # environment`,
+			``,
+			`[server]`,
+			`hosts = ["a", "b"] # /etc/test-app/test-app.config.toml`,
+			`port = "9000" # flags`,
+			``,
+			`[[channels]]`,
+			`name = "one" # /etc/test-app/test-app.config.toml`,
+			``,
+			`[[channels]]`,
+			`name = "two" # /etc/test-app/test-app.config.toml`,
+			``,
+		}, "\n")
+		if string(dump) != expectedDump {
+			t.Fatalf("expected:\n%s\ngot:\n%s", expectedDump, dump)
+		}
+
+		dumpPath := filepath.Join(t.TempDir(), "dump.toml")
+		writeTestFile(t, dumpPath, string(dump))
+		reloadedConf, err := orale.LoadFromValues([]string{}, "", []string{}, filepath.Dir(dumpPath), []string{"dump.toml"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		testConf := TestConfig{}
+		if err := reloadedConf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.Server.Port != 9000 || len(testConf.Server.Hosts) != 2 || len(testConf.Channels) != 2 {
+			t.Fatalf("expected the dump to load back in, got %+v", testConf)
+		}
+	})
+
+	t.Run("should dump the merged values as json", func(t *testing.T) {
+		t.Parallel()
+
+		conf := newTestLoader()
+		dump, err := conf.WithSecretsOf(TestConfig{}).Dump(orale.FormatJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := map[string]any{}
+		if err := json.Unmarshal(dump, &values); err != nil {
+			t.Fatal(err)
+		}
+		if values["database"].(map[string]any)["password"] != "[REDACTED]" {
+			t.Fatalf("expected the password to be redacted, got %s", dump)
+		}
+		if values["server"].(map[string]any)["port"] != "9000" {
+			t.Fatalf("expected the port to be 9000, got %s", dump)
+		}
+
+		if _, err := conf.Dump(orale.FormatJSON, orale.WithSourceComments(conf)); err == nil {
+			t.Fatal("expected an error for json source comments")
+		}
+	})
+
+	t.Run("should not reveal secrets given as flags in source comments", func(t *testing.T) {
+		t.Parallel()
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{"--database--password=hunter2"}),
+			orale.WithEnviron([]string{}),
+			orale.WithSearchPaths(t.TempDir()),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dump, err := conf.WithSecretsOf(TestConfig{}).Dump(orale.FormatTOML, orale.WithSourceComments(conf))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedDump := strings.Join([]string{
+			`[database]`,
+			`password = "[REDACTED]" # --database--password (flags)`,
+			``,
+		}, "\n")
+		if string(dump) != expectedDump {
+			t.Fatalf("expected:\n%s\ngot:\n%s", expectedDump, dump)
+		}
+
+		secretsConf := conf.WithSecretsOf(TestConfig{})
+		dump, err = secretsConf.Dump(orale.FormatTOML, orale.WithSourceComments(secretsConf))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(dump), `password = "[REDACTED]" # flags`) {
+			t.Fatalf("expected secrets to be described by their source alone, got:\n%s", dump)
+		}
+	})
+
+	t.Run("should resolve slices and tables with the same precedence as get", func(t *testing.T) {
+		t.Parallel()
+
+		conf := &orale.Loader{
+			FlagValues: map[string][]any{
+				"server.hosts[0]": {"c"},
+				"server.hosts[1]": {"d"},
+				"database":        {"postgres://localhost"},
+			},
+			ConfigurationFiles: []*orale.File{
+				{
+					Path: "/etc/test-app/test-app.config.toml",
+					Values: map[string][]any{
+						"server.hosts[0]":   {"a"},
+						"server.hosts[1]":   {"b"},
+						"server.hosts[2]":   {"e"},
+						"database.host":     {"localhost"},
+						"database.password": {"hunter2"},
+					},
+				},
+			},
+		}
+		dump, err := conf.Dump(orale.FormatJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := map[string]any{}
+		if err := json.Unmarshal(dump, &values); err != nil {
+			t.Fatal(err)
+		}
+
+		hosts := values["server"].(map[string]any)["hosts"].([]any)
+		if len(hosts) != 2 || hosts[0] != "c" || hosts[1] != "d" {
+			t.Fatalf("expected the hosts to be [c d], got %s", dump)
+		}
+		if values["database"] != "postgres://localhost" {
+			t.Fatalf("expected the database flag to replace the table, got %s", dump)
+		}
+	})
+
+	t.Run("should marshal a populated configuration struct", func(t *testing.T) {
+		t.Parallel()
+
+		conf := newTestLoader()
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		marshaled, err := orale.Marshal(testConf, orale.FormatTOML, orale.WithSourceComments(conf))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedMarshaled := strings.Join([]string{
+			`timeout = "30s"`,
+			``,
+			`[database]`,
+			`password = "[REDACTED]" # environment`,
+			``,
+			`[server]`,
+			`hosts = ["a", "b"] # /etc/test-app/test-app.config.toml`,
+			`port = 9000 # flags`,
+			``,
+			`[[channels]]`,
+			`name = "one" # /etc/test-app/test-app.config.toml`,
+			``,
+			`[[channels]]`,
+			`name = "two" # /etc/test-app/test-app.config.toml`,
+			``,
+		}, "\n")
+		if string(marshaled) != expectedMarshaled {
+			t.Fatalf("expected:\n%s\ngot:\n%s", expectedMarshaled, marshaled)
+		}
+
+		marshaled, err = orale.Marshal(&testConf, orale.FormatJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(marshaled), "hunter2") || !strings.Contains(string(marshaled), `"port": 9000`) {
+			t.Fatalf("expected the port and a redacted password, got %s", marshaled)
+		}
+
+		if _, err := orale.Marshal(42, orale.FormatTOML); err == nil {
+			t.Fatal("expected an error for a value that is not a struct")
+		}
+	})
+}
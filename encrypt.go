@@ -0,0 +1,185 @@
+package orale
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// encryptedValuePrefix marks a string value encrypted with EncryptValue.
+const encryptedValuePrefix = "enc:v1:"
+
+// GenerateKey generates a random 256 bit key for EncryptValue. The key is base64
+// encoded so it can be stored in a key file or environment variable.
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// EncryptValue encrypts a value with AES-GCM using a base64 encoded key such as
+// one from GenerateKey. The result has the form enc:v1:... and can be
+// committed in configuration files in place of the value. Load decrypts such
+// values when given the key.
+func EncryptValue(key, value string) (string, error) {
+	aead, err := newValueCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedValuePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptValue decrypts a value produced by EncryptValue. An error is returned
+// if the key is wrong or the value has been tampered with.
+func DecryptValue(key, encryptedValue string) (string, error) {
+	if !isEncryptedValue(encryptedValue) {
+		return "", fmt.Errorf("value is not encrypted")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encryptedValue, encryptedValuePrefix))
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	aead, err := newValueCipher(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+	value, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(value), nil
+}
+
+func isEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, encryptedValuePrefix)
+}
+
+func newValueCipher(key string) (cipher.AEAD, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil {
+		return nil, fmt.Errorf("malformed key: %w", err)
+	}
+	block, err := aes.NewCipher(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("malformed key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// takeDecryptionKey removes the reserved decryption key path, along with its
+// file reference, from the values and returns the key they hold. If only the
+// file reference is given the key is read from the file it names, whether or
+// not file references are enabled. Relative file paths are resolved against
+// baseDir.
+func takeDecryptionKey(decryptionKeyPath string, values map[string][]any, baseDir string) (string, error) {
+	if decryptionKeyPath == "" {
+		return "", nil
+	}
+	keyValues := values[decryptionKeyPath]
+	keyFileValues := values[decryptionKeyPath+fileReferenceSuffix]
+	delete(values, decryptionKeyPath)
+	delete(values, decryptionKeyPath+fileReferenceSuffix)
+
+	key := ""
+	for _, keyValue := range keyValues {
+		if str, ok := keyValue.(string); ok && str != "" {
+			key = str
+		}
+	}
+	if key != "" {
+		return key, nil
+	}
+
+	for _, keyFileValue := range keyFileValues {
+		keyFilePath, ok := keyFileValue.(string)
+		if !ok || keyFilePath == "" {
+			continue
+		}
+		if baseDir != "" && !filepath.IsAbs(keyFilePath) {
+			keyFilePath = filepath.Join(baseDir, keyFilePath)
+		}
+		keyBytes, err := os.ReadFile(keyFilePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read decryption key file: %w", err)
+		}
+		key = strings.TrimSpace(string(keyBytes))
+	}
+	return key, nil
+}
+
+// loadDecryptionKey returns the key given by options, reading it from the key
+// file if one was given. If neither was given the key is read from config.key
+// in the user's configuration directory for the application, if it exists.
+func loadDecryptionKey(options *loadOptions) (string, error) {
+	if options.decryptionKey != "" {
+		return options.decryptionKey, nil
+	}
+	if options.decryptionKeyFilePath != "" {
+		keyBytes, err := os.ReadFile(options.decryptionKeyFilePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read decryption key file: %w", err)
+		}
+		return strings.TrimSpace(string(keyBytes)), nil
+	}
+	if options.applicationName == "" {
+		return "", nil
+	}
+
+	keyFilePaths := []string{}
+	if configHomeDir := options.getenv("XDG_CONFIG_HOME"); configHomeDir != "" {
+		keyFilePaths = append(keyFilePaths, filepath.Join(configHomeDir, options.applicationName, "config.key"))
+	}
+	if homeDir := options.getenv("HOME"); homeDir != "" {
+		keyFilePaths = append(keyFilePaths, filepath.Join(homeDir, ".config", options.applicationName, "config.key"))
+	}
+	for _, keyFilePath := range keyFilePaths {
+		keyBytes, err := os.ReadFile(keyFilePath)
+		if err != nil {
+			continue
+		}
+		return strings.TrimSpace(string(keyBytes)), nil
+	}
+	return "", nil
+}
+
+// decryptSourceValues decrypts the encrypted string values of each source in
+// place and returns the paths of the values decrypted. An error is returned if
+// an encrypted value is found and the key is empty or wrong.
+func decryptSourceValues(sources []Source, key string) ([]string, error) {
+	decryptedPaths := []string{}
+	for _, source := range sources {
+		for path, values := range source.FlatValues() {
+			for i, value := range values {
+				str, ok := value.(string)
+				if !ok || !isEncryptedValue(str) {
+					continue
+				}
+				if key == "" {
+					return nil, fmt.Errorf("cannot decrypt value at path %s from %s: no decryption key was given", path, source.Name())
+				}
+				decryptedValue, err := DecryptValue(key, str)
+				if err != nil {
+					return nil, fmt.Errorf("cannot decrypt value at path %s from %s: %w", path, source.Name(), err)
+				}
+				values[i] = decryptedValue
+				decryptedPaths = append(decryptedPaths, path)
+			}
+		}
+	}
+	return decryptedPaths, nil
+}
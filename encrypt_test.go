@@ -0,0 +1,132 @@
+package orale_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	orale "github.com/RobertWHurst/orale"
+)
+
+func TestEncryptValue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should encrypt values that decrypt with the same key", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := orale.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		encryptedValue, err := orale.EncryptValue(key, "hunter2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(encryptedValue, "enc:v1:") || strings.Contains(encryptedValue, "hunter2") {
+			t.Fatalf("expected an encrypted value, got %s", encryptedValue)
+		}
+		value, err := orale.DecryptValue(key, encryptedValue)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != "hunter2" {
+			t.Fatalf("expected hunter2, got %s", value)
+		}
+	})
+
+	t.Run("should not decrypt values with the wrong key or that have been tampered with", func(t *testing.T) {
+		t.Parallel()
+
+		key, _ := orale.GenerateKey()
+		otherKey, _ := orale.GenerateKey()
+		encryptedValue, err := orale.EncryptValue(key, "hunter2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := orale.DecryptValue(otherKey, encryptedValue); err == nil {
+			t.Fatal("expected an error for the wrong key")
+		}
+		tamperedValue := encryptedValue[:len(encryptedValue)-4] + "AAA="
+		if _, err := orale.DecryptValue(key, tamperedValue); err == nil {
+			t.Fatal("expected an error for a tampered value")
+		}
+	})
+}
+
+func TestLoadEncryptedValues(t *testing.T) {
+	t.Parallel()
+
+	type TestConfig struct {
+		Db struct {
+			User     string `config:"user"`
+			Password string `config:"password"`
+		} `config:"db"`
+	}
+
+	key, err := orale.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryptedPassword, err := orale.EncryptValue(key, "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should decrypt values with a key from the environment or a key file", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tempDir, "test-app.config.toml"), "[db]\nuser=\"admin\"\npassword=\""+encryptedPassword+"\"\n")
+		writeTestFile(t, filepath.Join(tempDir, "keys", "test-app.key"), key+"\n")
+
+		for _, opts := range [][]orale.Option{
+			{orale.WithEnviron([]string{"TESTAPP__CONFIG_KEY=" + key})},
+			{orale.WithEnviron([]string{"TESTAPP__CONFIG_KEY_FILE=" + filepath.Join(tempDir, "keys", "test-app.key")})},
+			{orale.WithEnviron([]string{}), orale.WithDecryptionKeyFile(filepath.Join(tempDir, "keys", "test-app.key"))},
+			{orale.WithEnviron([]string{"HOME=" + tempDir}), orale.WithDecryptionKey(key)},
+		} {
+			conf, err := orale.Load("test-app", append([]orale.Option{
+				orale.WithArgs([]string{}),
+				orale.WithSearchPaths(tempDir),
+				orale.WithoutSystemSearchPaths(),
+			}, opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := conf.EnvironmentValues["config_key"]; ok {
+				t.Fatal("expected the config key to be reserved")
+			}
+
+			testConf := TestConfig{}
+			if err := conf.Get("", &testConf); err != nil {
+				t.Fatal(err)
+			}
+			if testConf.Db.User != "admin" || testConf.Db.Password != "hunter2" {
+				t.Fatalf("expected the password to be decrypted, got %+v", testConf.Db)
+			}
+		}
+	})
+
+	t.Run("should return an error when an encrypted value cannot be decrypted", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tempDir, "test-app.config.toml"), "[db]\npassword=\""+encryptedPassword+"\"\n")
+		otherKey, _ := orale.GenerateKey()
+
+		for _, environ := range [][]string{{"HOME=" + tempDir}, {"TESTAPP__CONFIG_KEY=" + otherKey}} {
+			_, err := orale.Load("test-app",
+				orale.WithArgs([]string{}),
+				orale.WithEnviron(environ),
+				orale.WithSearchPaths(tempDir),
+				orale.WithoutSystemSearchPaths(),
+			)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), "db.password") {
+				t.Fatalf("expected the error to name the path, got %s", err)
+			}
+		}
+	})
+}
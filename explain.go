@@ -0,0 +1,122 @@
+package orale
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Explanation describes where the value at a path came from.
+type Explanation struct {
+	// Path is the path that was explained.
+	Path string
+	// Value is the origin of the value Get uses for the path, or nil if no
+	// source provides it.
+	Value *Origin
+	// Shadowed lists the values from lower precedence sources that were
+	// overridden by Value, highest precedence first.
+	Shadowed []Origin
+	// Redacted is true if the path holds a secret. The values of its origins
+	// are left empty.
+	Redacted bool
+}
+
+// Origin describes a value provided by a source.
+type Origin struct {
+	// Source is the name of the source providing the value.
+	Source string
+	// Location is where the source found the value, for example the name of a
+	// flag or environment variable, or the path and line of a configuration
+	// file. It is empty if the source does not implement Locator.
+	Location string
+	// Value is the value as provided by the source. It is nil if the path holds
+	// a secret.
+	Value []any
+}
+
+// Explain reports which source provides the value at a path and where it was
+// found, along with the values of lower precedence sources it overrides. It
+// answers questions such as why the port is 9000 when the configuration file
+// says 8080. Values of paths holding secrets are redacted. A path holds a
+// secret if its value was encrypted or read from a secret file, or if it leads
+// to a Secret field of a type given to WithSecretsOf.
+func (l *Loader) Explain(path string) *Explanation {
+	explanation := &Explanation{
+		Path:     path,
+		Shadowed: []Origin{},
+		Redacted: l.isSensitive(path),
+	}
+	for _, source := range l.sources() {
+		value, ok := source.FlatValues()[path]
+		if !ok {
+			continue
+		}
+		origin := Origin{Source: source.Name()}
+		if locator, ok := source.(Locator); ok {
+			origin.Location = locator.Locate(path)
+		}
+		if !explanation.Redacted {
+			origin.Value = value
+		}
+
+		if explanation.Value == nil {
+			explanation.Value = &origin
+		} else {
+			explanation.Shadowed = append(explanation.Shadowed, origin)
+		}
+	}
+	return explanation
+}
+
+// String formats the explanation for people, for example:
+//
+//	server.port = 9000 from --server--port (flags)
+//	  shadows 8000 from MY_APP__SERVER__PORT (environment)
+//	  shadows 8080 from /etc/my-app/my-app.config.toml:12
+func (e *Explanation) String() string {
+	if e.Value == nil {
+		return fmt.Sprintf("%s is not set", e.Path)
+	}
+	explanation := fmt.Sprintf("%s = %s from %s", e.Path, e.formatValue(e.Value.Value), e.Value.describe())
+	for _, origin := range e.Shadowed {
+		explanation += fmt.Sprintf("\n  shadows %s from %s", e.formatValue(origin.Value), origin.describe())
+	}
+	return explanation
+}
+
+func (e *Explanation) formatValue(value []any) string {
+	if e.Redacted {
+		return redactedText
+	}
+	return formatExplainedValue(value)
+}
+
+// describe returns the location of the value along with the source name,
+// unless the location already names the source as file locations do.
+func (o Origin) describe() string {
+	switch {
+	case o.Location == "":
+		return o.Source
+	case strings.HasPrefix(o.Location, o.Source):
+		return o.Location
+	default:
+		return fmt.Sprintf("%s (%s)", o.Location, o.Source)
+	}
+}
+
+// formatExplainedValue formats a source's value. Strings are quoted and
+// multiple values are listed in square brackets.
+func formatExplainedValue(value []any) string {
+	formattedValues := []string{}
+	for _, subValue := range value {
+		if str, ok := subValue.(string); ok {
+			formattedValues = append(formattedValues, strconv.Quote(str))
+			continue
+		}
+		formattedValues = append(formattedValues, fmt.Sprint(subValue))
+	}
+	if len(formattedValues) == 1 {
+		return formattedValues[0]
+	}
+	return "[" + strings.Join(formattedValues, ", ") + "]"
+}
@@ -0,0 +1,207 @@
+package orale_test
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	orale "github.com/RobertWHurst/orale"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should report the winning value and the values it shadows", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		configPath := filepath.Join(tempDir, "test-app.config.toml")
+		writeTestFile(t, configPath, strings.Join([]string{
+			`# comment = "not a key"`,
+			`name = "app"`,
+			`description = """`,
+			`port = 1`,
+			`"""`,
+			``,
+			`[server]`,
+			`hosts = [`,
+			`  "a",`,
+			`]`,
+			`port = 8080`,
+			``,
+			`[[channels]]`,
+			`name = "one"`,
+			``,
+			`[[channels]]`,
+			`name = "two"`,
+		}, "\n"))
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{"--server--port=9000"}),
+			orale.WithEnviron([]string{"TESTAPP__SERVER__PORT=8000"}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		explanation := conf.Explain("server.port")
+		if explanation.Value == nil || explanation.Value.Location != "--server--port" || explanation.Value.Value[0] != "9000" {
+			t.Fatalf("expected the flag to win, got %+v", explanation.Value)
+		}
+		if len(explanation.Shadowed) != 2 {
+			t.Fatalf("expected 2 shadowed values, got %+v", explanation.Shadowed)
+		}
+		if explanation.Shadowed[0].Location != "TESTAPP__SERVER__PORT" || explanation.Shadowed[0].Value[0] != "8000" {
+			t.Fatalf("expected the environment variable to be shadowed, got %+v", explanation.Shadowed[0])
+		}
+		if explanation.Shadowed[1].Location != configPath+":11" || explanation.Shadowed[1].Value[0] != int64(8080) {
+			t.Fatalf("expected line 11 of the configuration file to be shadowed, got %+v", explanation.Shadowed[1])
+		}
+
+		expectedString := strings.Join([]string{
+			`server.port = "9000" from --server--port (flags)`,
+			`  shadows "8000" from TESTAPP__SERVER__PORT (environment)`,
+			`  shadows 8080 from ` + configPath + `:11`,
+		}, "\n")
+		if explanation.String() != expectedString {
+			t.Fatalf("expected:\n%s\ngot:\n%s", expectedString, explanation.String())
+		}
+
+		for path, expectedLine := range map[string]int{
+			"name":             2,
+			"description":      3,
+			"server.hosts[0]":  8,
+			"channels[0].name": 14,
+			"channels[1].name": 17,
+		} {
+			location := conf.Explain(path).Value.Location
+			if location != configPath+":"+strconv.Itoa(expectedLine) {
+				t.Fatalf("expected %s to be on line %d, got %s", path, expectedLine, location)
+			}
+		}
+
+		if explanation := conf.Explain("missing"); explanation.Value != nil || explanation.String() != "missing is not set" {
+			t.Fatalf("expected missing to not be set, got %s", explanation)
+		}
+	})
+
+	t.Run("should locate values in yaml, json and dotenv files", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		yamlPath := filepath.Join(tempDir, "test-app.config.yaml")
+		jsonPath := filepath.Join(tempDir, "test-app.config.json")
+		dotenvPath := filepath.Join(tempDir, ".env")
+		writeTestFile(t, yamlPath, "server:\n  hosts:\n    - a\n    - b\n")
+		writeTestFile(t, jsonPath, "{\n  \"server\": {\n    \"hosts\": [\n      \"c\",\n      \"d\"\n    ]\n  }\n}\n")
+		writeTestFile(t, dotenvPath, "# comment\nTESTAPP__SERVER__NAME=dotenv\n")
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		explanation := conf.Explain("server.hosts[1]")
+		if explanation.Value.Location != yamlPath+":4" {
+			t.Fatalf("expected line 4 of the yaml file, got %s", explanation.Value.Location)
+		}
+		if len(explanation.Shadowed) != 1 || explanation.Shadowed[0].Location != jsonPath+":5" {
+			t.Fatalf("expected line 5 of the json file to be shadowed, got %+v", explanation.Shadowed)
+		}
+		if location := conf.Explain("server.name").Value.Location; location != dotenvPath+":2" {
+			t.Fatalf("expected line 2 of the dotenv file, got %s", location)
+		}
+	})
+
+	t.Run("should redact secrets", func(t *testing.T) {
+		t.Parallel()
+
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{"database.password": {"hunter2"}},
+		}
+		type TestConfig struct {
+			Database struct {
+				Password orale.Secret[string] `config:"password"`
+			} `config:"database"`
+		}
+
+		if conf.Explain("database.password").Redacted {
+			t.Fatal("expected the value not to be redacted without the configuration type")
+		}
+		if err := conf.Get("", &TestConfig{}); err != nil {
+			t.Fatal(err)
+		}
+		if conf.Explain("database.password").Redacted {
+			t.Fatal("expected Get not to change the loader")
+		}
+
+		explanation := conf.WithSecretsOf(&TestConfig{}).Explain("database.password")
+		if !explanation.Redacted || explanation.Value.Value != nil {
+			t.Fatalf("expected the value to be redacted, got %+v", explanation.Value)
+		}
+		if strings.Contains(explanation.String(), "hunter2") || !strings.Contains(explanation.String(), "[REDACTED]") {
+			t.Fatalf("expected the value to be redacted, got %s", explanation)
+		}
+	})
+
+	t.Run("should not reveal secrets given as flags", func(t *testing.T) {
+		t.Parallel()
+
+		type TestConfig struct {
+			Database struct {
+				Password orale.Secret[string] `config:"password"`
+			} `config:"database"`
+		}
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{"--database--password=hunter2"}),
+			orale.WithEnviron([]string{}),
+			orale.WithSearchPaths(t.TempDir()),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		explanation := conf.WithSecretsOf(TestConfig{}).Explain("database.password")
+		if explanation.Value == nil || explanation.Value.Location != "--database--password" {
+			t.Fatalf("expected the flag to be located by its name, got %+v", explanation.Value)
+		}
+		if strings.Contains(explanation.String(), "hunter2") {
+			t.Fatalf("expected the secret not to be revealed, got %s", explanation)
+		}
+	})
+
+	t.Run("should redact secrets nested in slices and maps", func(t *testing.T) {
+		t.Parallel()
+
+		type TestConfig struct {
+			Peers []struct {
+				Host  string               `config:"host"`
+				Token orale.Secret[string] `config:"token"`
+			} `config:"peers"`
+			ApiKeys map[string]orale.Secret[string] `config:"api_keys"`
+			Tokens  []orale.Secret[string]          `config:"tokens"`
+		}
+		conf := (&orale.Loader{}).WithSecretsOf(TestConfig{})
+
+		for path, expectedRedacted := range map[string]bool{
+			"peers[1].token":   true,
+			"peers[1].host":    false,
+			"api_keys.billing": true,
+			"tokens":           true,
+			"tokens[0]":        true,
+		} {
+			if redacted := conf.Explain(path).Redacted; redacted != expectedRedacted {
+				t.Fatalf("expected %s to have Redacted %t, got %t", path, expectedRedacted, redacted)
+			}
+		}
+	})
+}
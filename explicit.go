@@ -0,0 +1,62 @@
+package orale
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// takeExplicitConfigPaths removes the reserved config path key from the flag
+// and environment values and returns the configuration file paths they hold.
+// Paths given by flags replace those given by the environment. If the key is
+// empty no paths are taken.
+func takeExplicitConfigPaths(configPathKey string, flagValues, environmentValues map[string][]any) []string {
+	if configPathKey == "" {
+		return nil
+	}
+
+	flagPaths := flagValues[configPathKey]
+	environmentPaths := environmentValues[configPathKey]
+	delete(flagValues, configPathKey)
+	delete(environmentValues, configPathKey)
+
+	configPaths := []string{}
+	if len(flagPaths) != 0 {
+		for _, flagPath := range flagPaths {
+			if str, ok := flagPath.(string); ok && str != "" {
+				configPaths = append(configPaths, str)
+			}
+		}
+		return configPaths
+	}
+	for _, environmentPath := range environmentPaths {
+		if str, ok := environmentPath.(string); ok {
+			for _, path := range strings.Split(str, string(os.PathListSeparator)) {
+				if path != "" {
+					configPaths = append(configPaths, path)
+				}
+			}
+		}
+	}
+	return configPaths
+}
+
+// loadExplicitConfigurationFiles loads the configuration files at the given
+// paths. Unlike searched files, a missing file is an error. Files are returned
+// in order of precedence, so the last path given comes first.
+func loadExplicitConfigurationFiles(configPaths []string, includes bool) ([]*File, error) {
+	configFiles := []*File{}
+	for i := len(configPaths) - 1; i >= 0; i -= 1 {
+		configPath, err := filepath.Abs(configPaths[i])
+		if err != nil {
+			return nil, err
+		}
+		configFile, err := loadFile(configPath, includes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load configuration file %s: %w", configPaths[i], err)
+		}
+		configFiles = append(configFiles, configFile)
+	}
+	return configFiles, nil
+}
@@ -1,17 +1,43 @@
 package orale
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the format of a configuration file.
+type Format string
+
+const (
+	// FormatTOML is the format of .toml files.
+	FormatTOML Format = "toml"
+	// FormatYAML is the format of .yaml and .yml files.
+	FormatYAML Format = "yaml"
+	// FormatJSON is the format of .json files.
+	FormatJSON Format = "json"
+	// FormatDotenv is the format of .env files.
+	FormatDotenv Format = "dotenv"
 )
 
 // File represents a configuration file loaded from disk.
 type File struct {
 	// Path is the absolute path to the configuration file.
 	Path string
+	// Format is the format the file was parsed as. It is derived from the file
+	// extension, with files of unknown extensions parsed as TOML. Dotenv files
+	// have the dotenv format.
+	Format Format
+	// Includes holds the paths of the files merged into this file by include
+	// and extends directives, in the order they were merged.
+	Includes []string
 	// Values is a map of configuration values loaded from the file. Note that
 	// these values are flattened into paths separated by periods. Slice indexes
 	// are represented by square brackets with the index inside. The value is
@@ -19,10 +45,30 @@ type File struct {
 	// file could have multiple values for the same path. This is not the case with
 	// toml so as of now it's always a slice of length 1.
 	Values map[string][]any
+
+	// lines holds the line each of the file's own keys is found on by path.
+	lines map[string]int
+	// ownPaths holds the paths of the values found in the file itself rather
+	// than merged in from included files.
+	ownPaths map[string]bool
+}
+
+// Name returns the path of the file. It allows File to be used as a Source.
+func (f *File) Name() string {
+	return f.Path
 }
 
-func maybeLoadFile(maybeConfigFilePath string) (*File, error) {
-	fileBytes, err := os.ReadFile(maybeConfigFilePath)
+// FlatValues returns the values of the file. It allows File to be used as a
+// Source.
+func (f *File) FlatValues() map[string][]any {
+	return f.Values
+}
+
+// maybeLoadFile loads a configuration file if it exists. Files that do not
+// exist or cannot be read due to their permissions are skipped by returning
+// nil.
+func maybeLoadFile(maybeConfigFilePath string, includes bool) (*File, error) {
+	file, err := loadFile(maybeConfigFilePath, includes)
 	if err != nil {
 		switch {
 		case os.IsNotExist(err), os.IsPermission(err):
@@ -31,28 +77,156 @@ func maybeLoadFile(maybeConfigFilePath string) (*File, error) {
 			return nil, err
 		}
 	}
+	return file, nil
+}
 
-	fileStr := string(fileBytes)
-
-	var hierarchicalFileValues map[string]any
-	if _, err := toml.Decode(fileStr, &hierarchicalFileValues); err != nil {
+// loadFile loads a configuration file, returning an error if it does not
+// exist. If includes is true, files referenced by include and extends
+// directives are merged in. Otherwise the directives are left as ordinary
+// values.
+func loadFile(configFilePath string, includes bool) (*File, error) {
+	fileBytes, err := os.ReadFile(configFilePath)
+	if err != nil {
 		return nil, err
 	}
+
+	format := formatFromPath(configFilePath)
+	hierarchicalFileValues, err := decodeFile(format, fileBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configFilePath, err)
+	}
+	ownValues := map[string][]any{}
+	flattenFileValues(nil, hierarchicalFileValues, ownValues)
+	ownPaths := map[string]bool{}
+	for ownPath := range ownValues {
+		ownPaths[ownPath] = true
+	}
+
+	includePaths := []string{}
+	if includes {
+		hierarchicalFileValues, includePaths, err = resolveIncludes(configFilePath, hierarchicalFileValues, []string{configFilePath})
+		if err != nil {
+			return nil, err
+		}
+	}
 	fileValues := map[string][]any{}
 	flattenFileValues(nil, hierarchicalFileValues, fileValues)
 
 	return &File{
-		Path:   maybeConfigFilePath,
-		Values: fileValues,
+		Path:     configFilePath,
+		Format:   format,
+		Includes: includePaths,
+		Values:   fileValues,
+		lines:    fileLines(format, fileBytes),
+		ownPaths: ownPaths,
 	}, nil
 }
 
+func formatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".json":
+		return FormatJSON
+	default:
+		return FormatTOML
+	}
+}
+
+func decodeFile(format Format, fileBytes []byte) (map[string]any, error) {
+	hierarchicalFileValues := map[string]any{}
+	switch format {
+	case FormatYAML:
+		var yamlValues any
+		if err := yaml.Unmarshal(fileBytes, &yamlValues); err != nil {
+			return nil, err
+		}
+		if yamlValues == nil {
+			return hierarchicalFileValues, nil
+		}
+		normalizedValues, ok := normalizeYAMLValue(yamlValues).(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected a mapping at the top level of the document")
+		}
+		return normalizedValues, nil
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(fileBytes))
+		decoder.UseNumber()
+		if err := decoder.Decode(&hierarchicalFileValues); err != nil {
+			return nil, err
+		}
+		return normalizeJSONValue(hierarchicalFileValues).(map[string]any), nil
+	default:
+		if _, err := toml.Decode(string(fileBytes), &hierarchicalFileValues); err != nil {
+			return nil, err
+		}
+		return hierarchicalFileValues, nil
+	}
+}
+
+// normalizeYAMLValue converts the values produced by the yaml decoder into the
+// types produced by the toml decoder so both formats are handled alike. Ints
+// become int64 and mappings with non string keys become map[string]any.
+func normalizeYAMLValue(value any) any {
+	switch val := value.(type) {
+	case int:
+		return int64(val)
+	case map[string]any:
+		for key, subValue := range val {
+			val[key] = normalizeYAMLValue(subValue)
+		}
+		return val
+	case map[any]any:
+		normalizedValues := map[string]any{}
+		for key, subValue := range val {
+			normalizedValues[fmt.Sprint(key)] = normalizeYAMLValue(subValue)
+		}
+		return normalizedValues
+	case []any:
+		for i, subValue := range val {
+			val[i] = normalizeYAMLValue(subValue)
+		}
+		return val
+	}
+	return value
+}
+
+// normalizeJSONValue converts the json.Number values produced by the json
+// decoder into the types produced by the toml decoder. Integers become int64,
+// or uint64 if too large for int64, so no precision is lost. All other numbers
+// become float64.
+func normalizeJSONValue(value any) any {
+	switch val := value.(type) {
+	case json.Number:
+		if intValue, err := val.Int64(); err == nil {
+			return intValue
+		}
+		if uintValue, err := strconv.ParseUint(val.String(), 10, 64); err == nil {
+			return uintValue
+		}
+		floatValue, _ := val.Float64()
+		return floatValue
+	case map[string]any:
+		for key, subValue := range val {
+			val[key] = normalizeJSONValue(subValue)
+		}
+		return val
+	case []any:
+		for i, subValue := range val {
+			val[i] = normalizeJSONValue(subValue)
+		}
+		return val
+	}
+	return value
+}
+
+// flattenFileValues flattens a file's values into paths. Null values, such as
+// those of empty YAML keys, are left out so their paths are unset.
 func flattenFileValues(pathChunks []string, hierarchicalValues map[string]any, flattenedValues map[string][]any) {
 	if pathChunks == nil {
 		pathChunks = []string{}
 	}
 
-	// TODO: deal with slice indexes
 	for key, value := range hierarchicalValues {
 		keyPathChunks := append(pathChunks, key)
 		keyPath := strings.Join(keyPathChunks, ".")
@@ -78,6 +252,13 @@ func flattenFileValues(pathChunks []string, hierarchicalValues map[string]any, f
 					}
 					subKeyPathChunks = append(subKeyPathChunks, chunk)
 				}
+				if v == nil {
+					continue
+				}
+				if subValues, ok := v.(map[string]any); ok {
+					flattenFileValues(subKeyPathChunks, subValues, flattenedValues)
+					continue
+				}
 				keyPath = strings.Join(subKeyPathChunks, ".")
 				if _, ok := flattenedValues[keyPath]; !ok {
 					flattenedValues[keyPath] = []any{}
@@ -86,6 +267,7 @@ func flattenFileValues(pathChunks []string, hierarchicalValues map[string]any, f
 			}
 		case map[string]any:
 			flattenFileValues(keyPathChunks, val, flattenedValues)
+		case nil:
 		default:
 			if _, ok := flattenedValues[keyPath]; !ok {
 				flattenedValues[keyPath] = []any{}
@@ -3,6 +3,7 @@ package orale
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"unicode"
 )
@@ -12,6 +13,30 @@ import (
 // loaded configuration values. Note that if the target contains paths which
 // are not present in the loaded configuration values, those paths will be
 // ignored allowing you to set defaults. Nil pointers will be initialized.
+// Maps with string keys are populated with every key found beneath their path.
+// Fields typed any receive the raw value, or the nested subtree rebuilt as
+// map[string]any and []any values. Types implementing Unmarshaler or
+// encoding.TextUnmarshaler decode their own values. time.Duration fields
+// accept strings such as 1h30m and time.Time fields accept RFC 3339 strings as
+// well as TOML date time values. Secret fields receive the value of the type
+// they wrap.
+//
+// Fields may declare a default with the `default` tag, for example
+// `default:"8080"`. The default is used when no source provides a value at or
+// beneath the field's path, and is converted using the same rules as
+// environment variables. Defaults for slices are split on commas so
+// `default:"a,b,c"` produces three entries.
+//
+// Fields may be marked as required with the `required` option, for example
+// `config:"connection_uri,required"`. If any required field is not provided by
+// a source a *MissingValuesError listing every missing path is returned.
+//
+// Once populated, fields are checked against the rules in their `validate`
+// tag, for example `validate:"min=1,max=65535"`. The supported rules are
+// omitempty, min, max, len, oneof, pattern, and the field comparisons eqfield,
+// nefield, gtfield, gtefield, ltfield and ltefield. If any rules fail a
+// *ValidationError listing each failure is returned. Finally AfterLoad and
+// Validate are called on every value implementing AfterLoader or Validator.
 //
 // Example:
 
@@ -52,9 +77,21 @@ func (l *Loader) Get(path string, target any) error {
 	if targetRefVal.Kind() != reflect.Ptr {
 		return fmt.Errorf("target must be a pointer")
 	}
+	if targetRefVal.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer")
+	}
 	targetRefVal = targetRefVal.Elem()
 
-	return getFromLoader(l, path, targetRefVal, 0)
+	if err := getFromLoader(l, path, targetRefVal, wholeValueIndex); err != nil {
+		return err
+	}
+	if err := checkRequired(l, path, targetRefVal); err != nil {
+		return err
+	}
+	if err := validate(l, path, targetRefVal); err != nil {
+		return err
+	}
+	return runHooks(l, path, targetRefVal)
 }
 
 // MustGet is the same as Get except it panics if an error occurs.
@@ -73,25 +110,44 @@ func (l *Loader) MustGetAll(target any) {
 	l.MustGet("", target)
 }
 
+// wholeValueIndex is passed to getFromLoader when the target should receive
+// all of the values at its path rather than a single entry of a multi value.
+const wholeValueIndex = -1
+
 func getFromLoader(l *Loader, currentPath string, targetRefVal reflect.Value, index int) error {
+	if ok, err := maybeDecodeSecret(l, currentPath, targetRefVal, index); ok || err != nil {
+		return err
+	}
+	if ok, err := maybeDecodeTime(l, currentPath, targetRefVal, index); ok || err != nil {
+		return err
+	}
+	if ok, err := maybeUnmarshal(l, currentPath, targetRefVal, index); ok || err != nil {
+		return err
+	}
+	valueIndex := index
+	if valueIndex == wholeValueIndex {
+		valueIndex = 0
+	}
+
 	switch targetRefVal.Kind() {
 	case reflect.Ptr:
 		if targetRefVal.IsNil() {
 			targetRefVal.Set(reflect.New(targetRefVal.Type().Elem()))
 		}
-		return getFromLoader(l, currentPath, targetRefVal.Elem(), 0)
+		return getFromLoader(l, currentPath, targetRefVal.Elem(), index)
 
 	case reflect.Struct:
 		for i := 0; i < targetRefVal.NumField(); i += 1 {
 			field := targetRefVal.Type().Field(i)
-			fieldTag := field.Tag.Get("config")
-			if fieldTag == "" {
-				fieldTag = calDefaultFieldTag(field.Name)
+			if !field.IsExported() {
+				continue
 			}
-			if currentPath != "" {
-				fieldTag = currentPath + "." + fieldTag
+			fieldPath := joinPath(currentPath, parseConfigTag(field).name)
+			fieldLoader := l
+			if defaultTag, ok := field.Tag.Lookup("default"); ok && !hasPath(l, fieldPath) {
+				fieldLoader = newDefaultLoader(fieldPath, defaultTag, field.Type)
 			}
-			if err := getFromLoader(l, fieldTag, targetRefVal.Field(i), 0); err != nil {
+			if err := getFromLoader(fieldLoader, fieldPath, targetRefVal.Field(i), wholeValueIndex); err != nil {
 				return err
 			}
 		}
@@ -107,12 +163,12 @@ func getFromLoader(l *Loader, currentPath string, targetRefVal reflect.Value, in
 		if valueLen > 0 {
 			targetRefVal.Set(reflect.MakeSlice(targetRefVal.Type(), valueLen, valueLen))
 			for i := 0; i < valueLen; i += 1 {
-				if err := getFromLoader(l, fmt.Sprintf("%s[%d]", currentPath, i), targetRefVal.Index(i), 0); err != nil {
+				if err := getFromLoader(l, fmt.Sprintf("%s[%d]", currentPath, i), targetRefVal.Index(i), wholeValueIndex); err != nil {
 					return err
 				}
 			}
 		} else {
-			value, err := resolveValue(l, currentPath)
+			value, _, err := resolveValue(l, currentPath)
 			if err != nil {
 				return err
 			}
@@ -128,73 +184,114 @@ func getFromLoader(l *Loader, currentPath string, targetRefVal reflect.Value, in
 			}
 		}
 
+	case reflect.Map:
+		if targetRefVal.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported map key type %s at path %s", targetRefVal.Type().Key(), currentPath)
+		}
+		keys, err := resolvePathKeys(l, currentPath)
+		if err != nil {
+			return err
+		}
+		if targetRefVal.IsNil() {
+			targetRefVal.Set(reflect.MakeMap(targetRefVal.Type()))
+		}
+		for _, key := range keys {
+			keyRefVal := reflect.ValueOf(unescapePathKey(key)).Convert(targetRefVal.Type().Key())
+			elemRefVal := reflect.New(targetRefVal.Type().Elem()).Elem()
+			if existingRefVal := targetRefVal.MapIndex(keyRefVal); existingRefVal.IsValid() {
+				elemRefVal.Set(existingRefVal)
+			}
+			if err := getFromLoader(l, joinPath(currentPath, key), elemRefVal, wholeValueIndex); err != nil {
+				return err
+			}
+			targetRefVal.SetMapIndex(keyRefVal, elemRefVal)
+		}
+
+	case reflect.Interface:
+		if targetRefVal.NumMethod() != 0 {
+			return fmt.Errorf("unsupported interface type %s at path %s", targetRefVal.Type(), currentPath)
+		}
+		if index != wholeValueIndex {
+			value, _, err := resolveValue(l, currentPath)
+			if err != nil {
+				return err
+			}
+			if len(value) > index {
+				targetRefVal.Set(reflect.ValueOf(value[index]))
+			}
+			return nil
+		}
+		subtree, err := resolveSubtree(l, currentPath, false)
+		if err != nil {
+			return err
+		}
+		if subtree != nil {
+			targetRefVal.Set(reflect.ValueOf(subtree))
+		}
+
 	case reflect.String:
-		value, err := resolveValue(l, currentPath)
+		value, source, err := resolveValue(l, currentPath)
 		if err != nil {
 			return err
 		}
-		if len(value) > index {
-			strValue, ok := value[index].(string)
-			if ok {
-				targetRefVal.SetString(strValue)
+		if len(value) > valueIndex {
+			strValue, err := convertToString(value[valueIndex])
+			if err != nil {
+				return newConversionError(currentPath, source, targetRefVal.Type(), value[valueIndex], err)
 			}
+			targetRefVal.SetString(strValue)
 		}
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		value, err := resolveValue(l, currentPath)
+		value, source, err := resolveValue(l, currentPath)
 		if err != nil {
 			return err
 		}
-		if len(value) > index {
-			int64Value, ok := value[index].(int64)
-			if ok {
-				targetRefVal.SetInt(int64Value)
+		if len(value) > valueIndex {
+			intValue, err := convertToInt(value[valueIndex], targetRefVal.Type().Bits())
+			if err != nil {
+				return newConversionError(currentPath, source, targetRefVal.Type(), value[valueIndex], err)
 			}
+			targetRefVal.SetInt(intValue)
 		}
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		value, err := resolveValue(l, currentPath)
+		value, source, err := resolveValue(l, currentPath)
 		if err != nil {
 			return err
 		}
-		if len(value) > index {
-			uint64Value, ok := value[index].(uint64)
-			if ok && len(value) > 0 {
-				targetRefVal.SetUint(uint64Value)
+		if len(value) > valueIndex {
+			uintValue, err := convertToUint(value[valueIndex], targetRefVal.Type().Bits())
+			if err != nil {
+				return newConversionError(currentPath, source, targetRefVal.Type(), value[valueIndex], err)
 			}
+			targetRefVal.SetUint(uintValue)
 		}
 
 	case reflect.Float32, reflect.Float64:
-		value, err := resolveValue(l, currentPath)
+		value, source, err := resolveValue(l, currentPath)
 		if err != nil {
 			return err
 		}
-		if len(value) > index {
-			float64Value, ok := value[index].(float64)
-			if ok {
-				targetRefVal.SetFloat(float64Value)
+		if len(value) > valueIndex {
+			floatValue, err := convertToFloat(value[valueIndex], targetRefVal.Type().Bits())
+			if err != nil {
+				return newConversionError(currentPath, source, targetRefVal.Type(), value[valueIndex], err)
 			}
+			targetRefVal.SetFloat(floatValue)
 		}
 
 	case reflect.Bool:
-		value, err := resolveValue(l, currentPath)
+		value, source, err := resolveValue(l, currentPath)
 		if err != nil {
 			return err
 		}
-		if len(value) > index {
-			if len(value) > 0 {
-				val, ok := value[index].(bool)
-				if !ok {
-					var valStr string
-					valStr, ok = value[index].(string)
-					if ok {
-						val = strings.ToLower(valStr) == "true"
-					}
-				}
-				if ok {
-					targetRefVal.SetBool(val)
-				}
+		if len(value) > valueIndex {
+			boolValue, err := convertToBool(value[valueIndex])
+			if err != nil {
+				return newConversionError(currentPath, source, targetRefVal.Type(), value[valueIndex], err)
 			}
+			targetRefVal.SetBool(boolValue)
 		}
 
 	default:
@@ -203,22 +300,20 @@ func getFromLoader(l *Loader, currentPath string, targetRefVal reflect.Value, in
 	return nil
 }
 
-func resolveValue(l *Loader, targetPath string) ([]any, error) {
+// resolveValue returns the values found at the target path along with the
+// name of the source they were taken from. Sources are consulted in order so
+// flags take precedence over environment variables, which take precedence
+// over configuration files.
+func resolveValue(l *Loader, targetPath string) ([]any, string, error) {
 	if targetPath == "" {
-		return nil, fmt.Errorf("target path cannot be empty")
+		return nil, "", fmt.Errorf("target path cannot be empty")
 	}
-	if value, ok := l.FlagValues[targetPath]; ok {
-		return value, nil
-	} else if value, ok := l.EnvironmentValues[targetPath]; ok {
-		return value, nil
-	} else {
-		for _, file := range l.ConfigurationFiles {
-			if value, ok := file.Values[targetPath]; ok {
-				return value, nil
-			}
+	for _, source := range l.sources() {
+		if value, ok := source.FlatValues()[targetPath]; ok {
+			return value, source.Name(), nil
 		}
 	}
-	return nil, nil
+	return nil, "", nil
 }
 
 func resolvePathLen(l *Loader, targetPath string) (int, error) {
@@ -226,46 +321,70 @@ func resolvePathLen(l *Loader, targetPath string) (int, error) {
 		return 0, fmt.Errorf("target path cannot be empty")
 	}
 
-	flagPaths := map[string]bool{}
-	for flagPath := range l.FlagValues {
-		slicePath := getSlicePathFromSubjectAndTargetPaths(flagPath, targetPath)
-		if slicePath != "" {
-			flagPaths[slicePath] = true
+	for _, source := range l.sources() {
+		if sourceLen := sourcePathLen(source, targetPath); sourceLen != 0 {
+			return sourceLen, nil
 		}
 	}
-	if len(flagPaths) != 0 {
-		return len(flagPaths), nil
-	}
 
-	environmentPaths := map[string]bool{}
-	for environmentPath := range l.EnvironmentValues {
-		slicePath := getSlicePathFromSubjectAndTargetPaths(environmentPath, targetPath)
+	return 0, nil
+}
+
+// sourcePathLen returns the number of slice entries the source provides
+// directly beneath the target path.
+func sourcePathLen(source Source, targetPath string) int {
+	sourcePaths := map[string]bool{}
+	for sourcePath := range source.FlatValues() {
+		slicePath := getSlicePathFromSubjectAndTargetPaths(sourcePath, targetPath)
 		if slicePath != "" {
-			environmentPaths[slicePath] = true
+			sourcePaths[slicePath] = true
 		}
 	}
-	if len(environmentPaths) != 0 {
-		return len(environmentPaths), nil
-	}
+	return len(sourcePaths)
+}
 
-	for _, file := range l.ConfigurationFiles {
-		filePaths := map[string]bool{}
-		for filePath := range file.Values {
-			slicePath := getSlicePathFromSubjectAndTargetPaths(filePath, targetPath)
-			if slicePath != "" {
-				filePaths[slicePath] = true
+// resolvePathKeys returns the sorted, unique keys found directly beneath the
+// target path in every source. It is used to populate maps.
+func resolvePathKeys(l *Loader, targetPath string) ([]string, error) {
+	keySet := map[string]bool{}
+	for _, source := range l.sources() {
+		for sourcePath := range source.FlatValues() {
+			if key := getKeyFromSubjectAndTargetPaths(sourcePath, targetPath); key != "" {
+				keySet[key] = true
 			}
 		}
-		if len(filePaths) != 0 {
-			return len(filePaths), nil
-		}
 	}
 
-	return 0, nil
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+func getKeyFromSubjectAndTargetPaths(subjectPath, targetPath string) string {
+	remainingPath := subjectPath
+	if targetPath != "" {
+		if len(subjectPath) < len(targetPath)+2 || subjectPath[:len(targetPath)] != targetPath || subjectPath[len(targetPath)] != '.' {
+			return ""
+		}
+		remainingPath = subjectPath[len(targetPath)+1:]
+	}
+	for i := 0; i < len(remainingPath); i += 1 {
+		switch remainingPath[i] {
+		case '\\':
+			i += 1
+		case '.', '[':
+			return remainingPath[:i]
+		}
+	}
+	return remainingPath
 }
 
 func getSlicePathFromSubjectAndTargetPaths(subjectPath, targetPath string) string {
-	if len(subjectPath) < len(targetPath)+3 {
+	if len(subjectPath) < len(targetPath)+3 || subjectPath[:len(targetPath)] != targetPath {
 		return ""
 	}
 	remainingPath := subjectPath[len(targetPath):]
@@ -1,7 +1,9 @@
 package orale_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	orale "github.com/RobertWHurst/orale"
 )
@@ -58,6 +60,22 @@ func newTestLoaderMultiValues() *orale.Loader {
 func TestGet(t *testing.T) {
 	t.Parallel()
 
+	t.Run("should return an error for nil and non pointer targets", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Timeout time.Duration `config:"timeout"`
+		}
+		conf := &orale.Loader{}
+
+		if err := conf.Get("", (*TestStruct)(nil)); err == nil || err.Error() != "target must be a non-nil pointer" {
+			t.Fatalf("expected a nil pointer error, got %v", err)
+		}
+		if err := conf.Get("", TestStruct{}); err == nil || err.Error() != "target must be a pointer" {
+			t.Fatalf("expected a pointer error, got %v", err)
+		}
+	})
+
 	t.Run("should correctly resolve values into struct", func(t *testing.T) {
 		t.Parallel()
 
@@ -212,3 +230,621 @@ func TestGet(t *testing.T) {
 		}
 	})
 }
+
+func TestGetStringConversion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should parse string values into numeric and bool fields", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Int      int     `config:"int"`
+			Int8     int8    `config:"int8"`
+			Hex      int     `config:"hex"`
+			Octal    int     `config:"octal"`
+			Binary   uint8   `config:"binary"`
+			Thousand int64   `config:"thousand"`
+			Exponent int     `config:"exponent"`
+			Uint     uint    `config:"uint"`
+			Float32  float32 `config:"float32"`
+			Float64  float64 `config:"float64"`
+			Bool     bool    `config:"bool"`
+		}
+
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{
+				"int":      {"3"},
+				"int8":     {"-128"},
+				"hex":      {"0x1F"},
+				"octal":    {"0o17"},
+				"binary":   {"0b1010"},
+				"thousand": {"1_000"},
+				"exponent": {"1e6"},
+				"uint":     {"42"},
+				"float32":  {"1.5"},
+				"float64":  {"2.5e-3"},
+				"bool":     {"TRUE"},
+			},
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if testStruct.Int != 3 {
+			t.Fatalf("expected Int to be 3, got %d", testStruct.Int)
+		}
+		if testStruct.Int8 != -128 {
+			t.Fatalf("expected Int8 to be -128, got %d", testStruct.Int8)
+		}
+		if testStruct.Hex != 31 {
+			t.Fatalf("expected Hex to be 31, got %d", testStruct.Hex)
+		}
+		if testStruct.Octal != 15 {
+			t.Fatalf("expected Octal to be 15, got %d", testStruct.Octal)
+		}
+		if testStruct.Binary != 10 {
+			t.Fatalf("expected Binary to be 10, got %d", testStruct.Binary)
+		}
+		if testStruct.Thousand != 1000 {
+			t.Fatalf("expected Thousand to be 1000, got %d", testStruct.Thousand)
+		}
+		if testStruct.Exponent != 1000000 {
+			t.Fatalf("expected Exponent to be 1000000, got %d", testStruct.Exponent)
+		}
+		if testStruct.Uint != 42 {
+			t.Fatalf("expected Uint to be 42, got %d", testStruct.Uint)
+		}
+		if testStruct.Float32 != 1.5 {
+			t.Fatalf("expected Float32 to be 1.5, got %f", testStruct.Float32)
+		}
+		if testStruct.Float64 != 0.0025 {
+			t.Fatalf("expected Float64 to be 0.0025, got %f", testStruct.Float64)
+		}
+		if !testStruct.Bool {
+			t.Fatal("expected Bool to be true")
+		}
+	})
+
+	t.Run("should return an error naming the path and source when parsing fails", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Port int `config:"port"`
+		}
+
+		conf := &orale.Loader{
+			FlagValues: map[string][]any{
+				"port": {"eighty"},
+			},
+		}
+
+		testStruct := TestStruct{}
+		err := conf.Get("", &testStruct)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !strings.Contains(err.Error(), "port") || !strings.Contains(err.Error(), "flags") {
+			t.Fatalf("expected error to name the path and source, got %s", err)
+		}
+	})
+
+	t.Run("should return an error when a string overflows the field", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Small int8  `config:"small"`
+			Big   uint8 `config:"big"`
+		}
+
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{
+				"small": {"1e3"},
+			},
+		}
+		if err := conf.Get("", &TestStruct{}); err == nil {
+			t.Fatal("expected an error for small")
+		}
+
+		conf = &orale.Loader{
+			EnvironmentValues: map[string][]any{
+				"big": {"256"},
+			},
+		}
+		if err := conf.Get("", &TestStruct{}); err == nil {
+			t.Fatal("expected an error for big")
+		}
+	})
+}
+
+func TestGetNumericConversion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should convert between numeric types", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Uint    uint    `config:"uint"`
+			Int32   int32   `config:"int32"`
+			Float64 float64 `config:"float64"`
+			Float32 float32 `config:"float32"`
+		}
+
+		conf := &orale.Loader{
+			ConfigurationFiles: []*orale.File{
+				{
+					Path: "path/to/file.toml",
+					Values: map[string][]any{
+						"uint":    {int64(8080)},
+						"int32":   {int64(-5)},
+						"float64": {int64(3)},
+						"float32": {float64(0.5)},
+					},
+				},
+			},
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if testStruct.Uint != 8080 {
+			t.Fatalf("expected Uint to be 8080, got %d", testStruct.Uint)
+		}
+		if testStruct.Int32 != -5 {
+			t.Fatalf("expected Int32 to be -5, got %d", testStruct.Int32)
+		}
+		if testStruct.Float64 != 3 {
+			t.Fatalf("expected Float64 to be 3, got %f", testStruct.Float64)
+		}
+		if testStruct.Float32 != 0.5 {
+			t.Fatalf("expected Float32 to be 0.5, got %f", testStruct.Float32)
+		}
+	})
+
+	t.Run("should return an error on overflow", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Int8 int8 `config:"int8"`
+		}
+
+		conf := &orale.Loader{
+			ConfigurationFiles: []*orale.File{
+				{
+					Path: "path/to/file.toml",
+					Values: map[string][]any{
+						"int8": {int64(300)},
+					},
+				},
+			},
+		}
+
+		err := conf.Get("", &TestStruct{})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !strings.Contains(err.Error(), "int8") || !strings.Contains(err.Error(), "path/to/file.toml") {
+			t.Fatalf("expected error to name the path, source and type, got %s", err)
+		}
+	})
+
+	t.Run("should return an error on negative values for unsigned fields", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Uint uint `config:"uint"`
+		}
+
+		conf := &orale.Loader{
+			ConfigurationFiles: []*orale.File{
+				{
+					Path:   "path/to/file.toml",
+					Values: map[string][]any{"uint": {int64(-1)}},
+				},
+			},
+		}
+
+		if err := conf.Get("", &TestStruct{}); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("should return an error on type mismatches", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Port int    `config:"port"`
+			Name string `config:"name"`
+		}
+
+		conf := &orale.Loader{
+			ConfigurationFiles: []*orale.File{
+				{
+					Path:   "path/to/file.toml",
+					Values: map[string][]any{"port": {true}},
+				},
+			},
+		}
+		if err := conf.Get("", &TestStruct{}); err == nil {
+			t.Fatal("expected an error for port")
+		}
+
+		conf = &orale.Loader{
+			ConfigurationFiles: []*orale.File{
+				{
+					Path:   "path/to/file.toml",
+					Values: map[string][]any{"name": {int64(1)}},
+				},
+			},
+		}
+		if err := conf.Get("", &TestStruct{}); err == nil {
+			t.Fatal("expected an error for name")
+		}
+	})
+}
+
+func TestGetMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should resolve string keyed maps from every source", func(t *testing.T) {
+		t.Parallel()
+
+		type Upstream struct {
+			Url     string `config:"url"`
+			Timeout int    `config:"timeout"`
+		}
+		type TestStruct struct {
+			Labels    map[string]string    `config:"labels"`
+			Upstreams map[string]*Upstream `config:"upstreams"`
+		}
+
+		conf := &orale.Loader{
+			FlagValues: map[string][]any{
+				"upstreams.auth.url": {"http://auth"},
+			},
+			EnvironmentValues: map[string][]any{
+				"upstreams.billing.url":     {"http://billing"},
+				"upstreams.billing.timeout": {"30"},
+			},
+			ConfigurationFiles: []*orale.File{
+				{
+					Path: "path/to/file.toml",
+					Values: map[string][]any{
+						"labels.team":            {"core"},
+						"labels.tier":            {"1"},
+						"upstreams.auth.timeout": {int64(10)},
+					},
+				},
+			},
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(testStruct.Labels) != 2 {
+			t.Fatalf("expected Labels to have 2 values, got %d", len(testStruct.Labels))
+		}
+		if testStruct.Labels["team"] != "core" {
+			t.Fatalf("expected Labels[team] to be core, got %s", testStruct.Labels["team"])
+		}
+		if testStruct.Labels["tier"] != "1" {
+			t.Fatalf("expected Labels[tier] to be 1, got %s", testStruct.Labels["tier"])
+		}
+		if len(testStruct.Upstreams) != 2 {
+			t.Fatalf("expected Upstreams to have 2 values, got %d", len(testStruct.Upstreams))
+		}
+		if testStruct.Upstreams["auth"].Url != "http://auth" {
+			t.Fatalf("expected Upstreams[auth].Url to be http://auth, got %s", testStruct.Upstreams["auth"].Url)
+		}
+		if testStruct.Upstreams["auth"].Timeout != 10 {
+			t.Fatalf("expected Upstreams[auth].Timeout to be 10, got %d", testStruct.Upstreams["auth"].Timeout)
+		}
+		if testStruct.Upstreams["billing"].Url != "http://billing" {
+			t.Fatalf("expected Upstreams[billing].Url to be http://billing, got %s", testStruct.Upstreams["billing"].Url)
+		}
+		if testStruct.Upstreams["billing"].Timeout != 30 {
+			t.Fatalf("expected Upstreams[billing].Timeout to be 30, got %d", testStruct.Upstreams["billing"].Timeout)
+		}
+	})
+
+	t.Run("should unescape periods in map keys from environment variables", func(t *testing.T) {
+		t.Parallel()
+
+		type Upstream struct {
+			Url string `config:"url,required"`
+		}
+		type TestStruct struct {
+			Labels    map[string]string   `config:"labels"`
+			Upstreams map[string]Upstream `config:"upstreams"`
+		}
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{
+				"TESTAPP__LABELS__EXAMPLE.COM=x",
+				"TESTAPP__UPSTREAMS__AUTH.EXAMPLE.COM__URL=http://auth",
+			}),
+			orale.WithSearchPaths(t.TempDir()),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+		if testStruct.Labels["example.com"] != "x" {
+			t.Fatalf("expected Labels[example.com] to be x, got %v", testStruct.Labels)
+		}
+		if testStruct.Upstreams["auth.example.com"].Url != "http://auth" {
+			t.Fatalf("expected Upstreams[auth.example.com].Url to be http://auth, got %v", testStruct.Upstreams)
+		}
+	})
+
+	t.Run("should keep existing map entries", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Labels map[string]string `config:"labels"`
+		}
+
+		conf := &orale.Loader{
+			FlagValues: map[string][]any{
+				"labels.team": {"core"},
+			},
+		}
+
+		testStruct := TestStruct{Labels: map[string]string{"env": "dev"}}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if testStruct.Labels["env"] != "dev" {
+			t.Fatalf("expected Labels[env] to be dev, got %s", testStruct.Labels["env"])
+		}
+		if testStruct.Labels["team"] != "core" {
+			t.Fatalf("expected Labels[team] to be core, got %s", testStruct.Labels["team"])
+		}
+	})
+}
+
+func TestGetInterface(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should resolve raw subtrees into any fields", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Name   any            `config:"name"`
+			Plugin any            `config:"plugin"`
+			Extra  map[string]any `config:"extra"`
+		}
+
+		conf := &orale.Loader{
+			FlagValues: map[string][]any{
+				"plugin.options.level": {"debug"},
+			},
+			ConfigurationFiles: []*orale.File{
+				{
+					Path: "path/to/file.toml",
+					Values: map[string][]any{
+						"name":                 {"orale"},
+						"plugin.options.level": {"info"},
+						"plugin.hosts[0].name": {"a"},
+						"plugin.hosts[1].name": {"b"},
+						"extra.retries":        {int64(3)},
+						"extra.tags[0]":        {"x"},
+					},
+				},
+			},
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if testStruct.Name != "orale" {
+			t.Fatalf("expected Name to be orale, got %v", testStruct.Name)
+		}
+
+		plugin, ok := testStruct.Plugin.(map[string]any)
+		if !ok {
+			t.Fatalf("expected Plugin to be a map, got %T", testStruct.Plugin)
+		}
+		options, ok := plugin["options"].(map[string]any)
+		if !ok {
+			t.Fatalf("expected Plugin.options to be a map, got %T", plugin["options"])
+		}
+		if options["level"] != "debug" {
+			t.Fatalf("expected Plugin.options.level to be debug, got %v", options["level"])
+		}
+		hosts, ok := plugin["hosts"].([]any)
+		if !ok {
+			t.Fatalf("expected Plugin.hosts to be a slice, got %T", plugin["hosts"])
+		}
+		if len(hosts) != 2 {
+			t.Fatalf("expected Plugin.hosts to have 2 values, got %d", len(hosts))
+		}
+		if hosts[1].(map[string]any)["name"] != "b" {
+			t.Fatalf("expected Plugin.hosts[1].name to be b, got %v", hosts[1])
+		}
+
+		if testStruct.Extra["retries"] != int64(3) {
+			t.Fatalf("expected Extra.retries to be 3, got %v", testStruct.Extra["retries"])
+		}
+		tags, ok := testStruct.Extra["tags"].([]any)
+		if !ok || len(tags) != 1 || tags[0] != "x" {
+			t.Fatalf("expected Extra.tags to be [x], got %v", testStruct.Extra["tags"])
+		}
+	})
+
+	t.Run("should resolve any fields with the same precedence as typed fields", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Hosts      any      `config:"hosts"`
+			HostNames  []string `config:"hosts"`
+			Database   any      `config:"database"`
+			DatabaseIP any      `config:"database_ip"`
+		}
+
+		conf := &orale.Loader{
+			FlagValues: map[string][]any{
+				"database.host": {"db.internal"},
+				"database_ip":   {"10.0.0.1"},
+			},
+			ConfigurationFiles: []*orale.File{
+				{
+					Path: "path/to/override.toml",
+					Values: map[string][]any{
+						"hosts[0]": {"a"},
+						"hosts[1]": {"b"},
+					},
+				},
+				{
+					Path: "path/to/file.toml",
+					Values: map[string][]any{
+						"hosts[0]":         {"x"},
+						"hosts[1]":         {"y"},
+						"hosts[2]":         {"z"},
+						"database":         {"postgres://localhost"},
+						"database_ip.v4":   {"127.0.0.1"},
+						"database_ip.port": {int64(5432)},
+					},
+				},
+			},
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		hosts, ok := testStruct.Hosts.([]any)
+		if !ok || len(hosts) != 2 || hosts[0] != "a" || hosts[1] != "b" {
+			t.Fatalf("expected Hosts to be [a b], got %v", testStruct.Hosts)
+		}
+		if len(testStruct.HostNames) != 2 {
+			t.Fatalf("expected HostNames to be [a b], got %v", testStruct.HostNames)
+		}
+		database, ok := testStruct.Database.(map[string]any)
+		if !ok || database["host"] != "db.internal" {
+			t.Fatalf("expected Database to be the table from flags, got %v", testStruct.Database)
+		}
+		if testStruct.DatabaseIP != "10.0.0.1" {
+			t.Fatalf("expected DatabaseIP to be the value from flags, got %v", testStruct.DatabaseIP)
+		}
+	})
+}
+
+func TestGetDefaults(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should use default tags when no source provides the path", func(t *testing.T) {
+		t.Parallel()
+
+		type Database struct {
+			Host string `config:"host" default:"localhost"`
+			Port int    `config:"port" default:"5432"`
+		}
+		type TestStruct struct {
+			Port     int           `config:"port" default:"8080"`
+			Names    []string      `config:"names" default:"a,b,c"`
+			Ratio    float64       `config:"ratio" default:"0.5"`
+			Debug    bool          `config:"debug" default:"true"`
+			Timeout  time.Duration `config:"timeout" default:"30s"`
+			Database *Database     `config:"database"`
+		}
+
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{
+				"database.host": {"db.internal"},
+			},
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if testStruct.Port != 8080 {
+			t.Fatalf("expected Port to be 8080, got %d", testStruct.Port)
+		}
+		if len(testStruct.Names) != 3 || testStruct.Names[0] != "a" || testStruct.Names[2] != "c" {
+			t.Fatalf("expected Names to be [a b c], got %v", testStruct.Names)
+		}
+		if testStruct.Ratio != 0.5 {
+			t.Fatalf("expected Ratio to be 0.5, got %f", testStruct.Ratio)
+		}
+		if !testStruct.Debug {
+			t.Fatal("expected Debug to be true")
+		}
+		if testStruct.Timeout != 30*time.Second {
+			t.Fatalf("expected Timeout to be 30s, got %s", testStruct.Timeout)
+		}
+		if testStruct.Database.Host != "db.internal" {
+			t.Fatalf("expected Database.Host to be db.internal, got %s", testStruct.Database.Host)
+		}
+		if testStruct.Database.Port != 5432 {
+			t.Fatalf("expected Database.Port to be 5432, got %d", testStruct.Database.Port)
+		}
+	})
+
+	t.Run("should prefer values from sources over default tags", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Port  int      `config:"port" default:"8080"`
+			Names []string `config:"names" default:"a,b,c"`
+		}
+
+		conf := &orale.Loader{
+			FlagValues: map[string][]any{
+				"port": {"9000"},
+			},
+			ConfigurationFiles: []*orale.File{
+				{
+					Path:   "path/to/file.toml",
+					Values: map[string][]any{"names[0]": {"x"}},
+				},
+			},
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if testStruct.Port != 9000 {
+			t.Fatalf("expected Port to be 9000, got %d", testStruct.Port)
+		}
+		if len(testStruct.Names) != 1 || testStruct.Names[0] != "x" {
+			t.Fatalf("expected Names to be [x], got %v", testStruct.Names)
+		}
+	})
+
+	t.Run("should return an error for invalid default tags", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Port int `config:"port" default:"http"`
+		}
+
+		conf := &orale.Loader{}
+		err := conf.Get("", &TestStruct{})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !strings.Contains(err.Error(), "default") {
+			t.Fatalf("expected error to name the default source, got %s", err)
+		}
+	})
+}
@@ -2,4 +2,7 @@ module github.com/RobertWHurst/orale
 
 go 1.21.4
 
-require github.com/BurntSushi/toml v1.3.2
+require (
+	github.com/BurntSushi/toml v1.3.2
+	gopkg.in/yaml.v3 v3.0.1
+)
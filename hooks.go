@@ -0,0 +1,120 @@
+package orale
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Validator can be implemented by configuration types that wish to check
+// their own invariants. Validate is called by Get once the type and all of
+// its nested values have been populated.
+type Validator interface {
+	Validate() error
+}
+
+// AfterLoader can be implemented by configuration types that need to compute
+// derived fields. AfterLoad is called by Get once the type and all of its
+// nested values have been populated, before Validate is called.
+type AfterLoader interface {
+	AfterLoad(l *Loader) error
+}
+
+// runHooks calls AfterLoad and Validate on the target and every nested value
+// implementing them. Values are followed as they are by walkFields, but
+// nested values are visited before the values containing them so a struct's
+// hooks can rely on its fields having already been checked. Errors are
+// wrapped with the config path of the failing value.
+func runHooks(l *Loader, currentPath string, targetRefVal reflect.Value) error {
+	switch targetRefVal.Kind() {
+	case reflect.Ptr:
+		if targetRefVal.IsNil() {
+			return nil
+		}
+		return runHooks(l, currentPath, targetRefVal.Elem())
+
+	case reflect.Interface:
+		if targetRefVal.IsNil() {
+			return nil
+		}
+		// Interface values are not addressable so the value is copied, given to
+		// the hooks, then stored back in case AfterLoad modified it.
+		elemRefVal := reflect.New(targetRefVal.Elem().Type()).Elem()
+		elemRefVal.Set(targetRefVal.Elem())
+		if err := runHooks(l, currentPath, elemRefVal); err != nil {
+			return err
+		}
+		if targetRefVal.CanSet() {
+			targetRefVal.Set(elemRefVal)
+		}
+		return nil
+
+	case reflect.Struct:
+		if targetRefVal.Type() == timeType {
+			return nil
+		}
+		if innerRefVal, ok := secretInnerValue(targetRefVal); ok {
+			return runHooks(l, currentPath, innerRefVal)
+		}
+		for i := 0; i < targetRefVal.NumField(); i += 1 {
+			field := targetRefVal.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := joinPath(currentPath, parseConfigTag(field).name)
+			if err := runHooks(l, fieldPath, targetRefVal.Field(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < targetRefVal.Len(); i += 1 {
+			if err := runHooks(l, fmt.Sprintf("%s[%d]", currentPath, i), targetRefVal.Index(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if targetRefVal.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		// Map values are not addressable so each is copied, given to the hooks,
+		// then stored back in case AfterLoad modified it. Keys are visited in
+		// order so the same error is returned on every run.
+		keys := targetRefVal.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, key := range keys {
+			elemRefVal := reflect.New(targetRefVal.Type().Elem()).Elem()
+			elemRefVal.Set(targetRefVal.MapIndex(key))
+			if err := runHooks(l, joinPath(currentPath, escapePathKey(key.String())), elemRefVal); err != nil {
+				return err
+			}
+			targetRefVal.SetMapIndex(key, elemRefVal)
+		}
+	}
+
+	if !targetRefVal.CanAddr() {
+		return nil
+	}
+	target := targetRefVal.Addr().Interface()
+	if afterLoader, ok := target.(AfterLoader); ok {
+		if err := afterLoader.AfterLoad(l); err != nil {
+			return newHookError("after load failed", currentPath, err)
+		}
+	}
+	if validator, ok := target.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return newHookError("validation failed", currentPath, err)
+		}
+	}
+	return nil
+}
+
+func newHookError(message, path string, err error) error {
+	if path == "" {
+		return fmt.Errorf("%s: %w", message, err)
+	}
+	return fmt.Errorf("%s at path %s: %w", message, path, err)
+}
@@ -0,0 +1,149 @@
+package orale_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	orale "github.com/RobertWHurst/orale"
+)
+
+type testHookDatabase struct {
+	Host string `config:"host"`
+	Port int    `config:"port"`
+	Addr string
+}
+
+func (d *testHookDatabase) AfterLoad(l *orale.Loader) error {
+	d.Addr = fmt.Sprintf("%s:%d", d.Host, d.Port)
+	return nil
+}
+
+func (d *testHookDatabase) Validate() error {
+	if d.Host == "" {
+		return errors.New("host must be set")
+	}
+	return nil
+}
+
+type testHookConfig struct {
+	Primary  testHookDatabase            `config:"primary"`
+	Replicas map[string]testHookDatabase `config:"replicas"`
+	Calls    []string
+}
+
+func (c *testHookConfig) AfterLoad(l *orale.Loader) error {
+	c.Calls = append(c.Calls, "after load")
+	return nil
+}
+
+func (c *testHookConfig) Validate() error {
+	c.Calls = append(c.Calls, "validate")
+	if c.Primary.Addr == "" {
+		return errors.New("expected nested hooks to run first")
+	}
+	return nil
+}
+
+func TestGetHooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should call AfterLoad and Validate on nested values", func(t *testing.T) {
+		t.Parallel()
+
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{
+				"primary.host":       {"db"},
+				"primary.port":       {"5432"},
+				"replicas.east.host": {"db-east"},
+				"replicas.east.port": {"5433"},
+			},
+		}
+
+		testConfig := testHookConfig{}
+		if err := conf.Get("", &testConfig); err != nil {
+			t.Fatal(err)
+		}
+
+		if testConfig.Primary.Addr != "db:5432" {
+			t.Fatalf("expected Primary.Addr to be db:5432, got %s", testConfig.Primary.Addr)
+		}
+		if testConfig.Replicas["east"].Addr != "db-east:5433" {
+			t.Fatalf("expected Replicas[east].Addr to be db-east:5433, got %s", testConfig.Replicas["east"].Addr)
+		}
+		if strings.Join(testConfig.Calls, ",") != "after load,validate" {
+			t.Fatalf("expected AfterLoad then Validate to be called, got %v", testConfig.Calls)
+		}
+	})
+
+	t.Run("should wrap Validate errors with the config path", func(t *testing.T) {
+		t.Parallel()
+
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{
+				"primary.host":       {"db"},
+				"replicas.west.port": {"5434"},
+			},
+		}
+
+		err := conf.Get("", &testHookConfig{})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !strings.Contains(err.Error(), "replicas.west") {
+			t.Fatalf("expected error to name the path replicas.west, got %s", err)
+		}
+		if !strings.Contains(err.Error(), "host must be set") {
+			t.Fatalf("expected error to wrap the Validate error, got %s", err)
+		}
+	})
+
+	t.Run("should return the error of the first map key", func(t *testing.T) {
+		t.Parallel()
+
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{
+				"primary.host":       {"db"},
+				"replicas.a.port":    {"5433"},
+				"replicas.b.port":    {"5434"},
+				"replicas.c.port":    {"5435"},
+				"replicas.d.port":    {"5436"},
+				"replicas.east.port": {"5437"},
+			},
+		}
+
+		for i := 0; i < 10; i += 1 {
+			err := conf.Get("", &testHookConfig{})
+			if err == nil || !strings.Contains(err.Error(), "replicas.a:") {
+				t.Fatalf("expected the error of replicas.a, got %v", err)
+			}
+		}
+	})
+
+	t.Run("should call hooks within secrets and interfaces", func(t *testing.T) {
+		t.Parallel()
+
+		type TestConfig struct {
+			Primary orale.Secret[testHookDatabase] `config:"primary"`
+			Replica any
+		}
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{
+				"primary.host": {"db"},
+				"primary.port": {"5432"},
+			},
+		}
+
+		testConfig := TestConfig{Replica: &testHookDatabase{Host: "db-east", Port: 5433}}
+		if err := conf.Get("", &testConfig); err != nil {
+			t.Fatal(err)
+		}
+		if addr := testConfig.Primary.Reveal().Addr; addr != "db:5432" {
+			t.Fatalf("expected Primary.Addr to be db:5432, got %s", addr)
+		}
+		if addr := testConfig.Replica.(*testHookDatabase).Addr; addr != "db-east:5433" {
+			t.Fatalf("expected Replica.Addr to be db-east:5433, got %s", addr)
+		}
+	})
+}
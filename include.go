@@ -0,0 +1,137 @@
+package orale
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Keys of the directives a configuration file may use to merge in other
+// files.
+const (
+	extendsDirectiveKey = "extends"
+	includeDirectiveKey = "include"
+)
+
+// resolveIncludes removes the extends and include directives from the top
+// level of a file's values and deep merges the referenced files beneath them.
+// Extended files are merged first, followed by included files in the order
+// given, and finally the file's own values, so later values take precedence.
+// Paths are resolved relative to the including file and may be glob patterns.
+// The stack holds the paths of the files currently being loaded so cycles can
+// be detected.
+func resolveIncludes(configFilePath string, hierarchicalValues map[string]any, stack []string) (map[string]any, []string, error) {
+	extendsPaths, err := takeDirectivePaths(configFilePath, hierarchicalValues, extendsDirectiveKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	includePaths, err := takeDirectivePaths(configFilePath, hierarchicalValues, includeDirectiveKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(extendsPaths) == 0 && len(includePaths) == 0 {
+		return hierarchicalValues, []string{}, nil
+	}
+
+	mergedValues := map[string]any{}
+	includes := []string{}
+	for _, includePath := range append(extendsPaths, includePaths...) {
+		for _, stackPath := range stack {
+			if stackPath == includePath {
+				return nil, nil, fmt.Errorf("include cycle detected: %s -> %s", strings.Join(stack, " -> "), includePath)
+			}
+		}
+
+		fileBytes, err := os.ReadFile(includePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to include %s from %s: %w", includePath, configFilePath, err)
+		}
+		includedValues, err := decodeFile(formatFromPath(includePath), fileBytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", includePath, err)
+		}
+		includeStack := append(append([]string{}, stack...), includePath)
+		includedValues, nestedIncludes, err := resolveIncludes(includePath, includedValues, includeStack)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		mergeHierarchicalValues(mergedValues, includedValues)
+		includes = append(includes, nestedIncludes...)
+		includes = append(includes, includePath)
+	}
+	mergeHierarchicalValues(mergedValues, hierarchicalValues)
+
+	return mergedValues, includes, nil
+}
+
+// takeDirectivePaths removes a directive from the top level of a file's
+// values and returns the absolute paths it references. The directive may be a
+// single path or a list of paths. Glob patterns are expanded in lexical order
+// to the matching files with a known configuration file extension, so a
+// pattern such as src/*.go matches nothing. Plain paths are returned even if
+// they do not exist so the caller can report them as missing.
+func takeDirectivePaths(configFilePath string, hierarchicalValues map[string]any, directiveKey string) ([]string, error) {
+	directiveValue, ok := hierarchicalValues[directiveKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(hierarchicalValues, directiveKey)
+
+	patterns := []string{}
+	switch val := directiveValue.(type) {
+	case string:
+		patterns = append(patterns, val)
+	case []any:
+		for _, entry := range val {
+			str, ok := entry.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected %s in %s to be a list of paths, found %T", directiveKey, configFilePath, entry)
+			}
+			patterns = append(patterns, str)
+		}
+	default:
+		return nil, fmt.Errorf("expected %s in %s to be a path or list of paths, found %T", directiveKey, configFilePath, directiveValue)
+	}
+
+	dirPath := filepath.Dir(configFilePath)
+	paths := []string{}
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dirPath, pattern)
+		}
+		if !strings.ContainsAny(pattern, "*?[") {
+			paths = append(paths, filepath.Clean(pattern))
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern in %s: %w", directiveKey, configFilePath, err)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			if isConfigFileName(match) {
+				paths = append(paths, match)
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+// mergeHierarchicalValues deep merges the source values into the target.
+// Tables present in both are merged while all other values in the source
+// replace those in the target.
+func mergeHierarchicalValues(target, source map[string]any) {
+	for key, sourceValue := range source {
+		sourceTable, sourceIsTable := sourceValue.(map[string]any)
+		targetTable, targetIsTable := target[key].(map[string]any)
+		if sourceIsTable && targetIsTable {
+			mergeHierarchicalValues(targetTable, sourceTable)
+			continue
+		}
+		target[key] = sourceValue
+	}
+}
@@ -12,14 +12,236 @@ import (
 // configuration files. Flags are taken from `os.Args[1:]`. Environment
 // variables are taken from `os.Environ()`. Configuration files are taken from
 // the working directory and all parent directories. The configuration file
-// name is the application name with the extension `.config.toml`. If the name
-// contain
-func Load(applicationName string) (*Loader, error) {
+// name is the application name with the extension `.config.toml`,
+// `.config.yaml`, `.config.yml` or `.config.json`. If more than one is found in
+// the same directory they take precedence in that order.
+//
+// After the working directory and its parents, configuration files are looked
+// for in the user and system configuration directories, each taking
+// precedence over the next:
+//
+//   - $XDG_CONFIG_HOME/my-app/
+//   - ~/.config/my-app/
+//   - ~/.my-app.config.toml (and the other extensions)
+//   - /etc/my-app/
+//
+// Within the my-app directories both my-app.config.toml and config.toml are
+// accepted, along with the other extensions.
+//
+// Beside each of these locations, fragments in a my-app.config.d directory are
+// loaded in lexical order on top of the my-app.config.toml beside them, with
+// later fragments taking precedence.
+//
+// Specific configuration files can be given with the reserved --config flag,
+// which may be repeated, or the MY_APP__CONFIG environment variable, which may
+// hold several paths separated by the OS path list separator. These files take
+// precedence over the files found by searching, with later files taking
+// precedence over earlier ones. Unlike searched files, a named file that does
+// not exist is an error. The reserved path is not available to Get.
+//
+// Profiles can be selected with the reserved --profile flag, the
+// MY_APP__PROFILE environment variable or the WithProfiles option. Several
+// profiles may be given separated by commas, for example production,eu-west.
+// In each searched directory the overlay of a profile, such as
+// my-app.production.config.toml, takes precedence over the base file, with
+// later profiles taking precedence over earlier ones.
+//
+// Variables in .env files found in the working directory and its parents are
+// loaded as if they were environment variables, though real environment
+// variables take precedence over them. A .env.<profile> file is loaded for
+// each profile on top of the .env file beside it. Only variables with the
+// MY_APP__ prefix are used, and the reserved config and profile variables
+// cannot be set from .env files.
+//
+// With the WithFileReferences option, environment variables ending in _FILE,
+// such as MY_APP__DB__PASSWORD_FILE=/run/secrets/db, have the contents of the
+// file they name loaded into the path without the suffix. If systemd passes
+// credentials in $CREDENTIALS_DIRECTORY, each credential file is loaded as a
+// value beneath the environment variables. See NewDirectorySource.
+//
+// String values of the form enc:v1:..., produced by EncryptValue, are
+// decrypted as they are loaded. The key is taken from the MY_APP__CONFIG_KEY
+// environment variable, the file named by MY_APP__CONFIG_KEY_FILE, the
+// WithDecryptionKey and WithDecryptionKeyFile options, or config.key in the
+// user's configuration directory such as ~/.config/my-app/config.key, in that
+// order. Loading fails if an encrypted value is found without a key.
+//
+// Each of these can be changed by passing options, for example:
+//
+//	loader, err := orale.Load("my-app",
+//		orale.WithEnvPrefix("LEGACY_APP"),
+//		orale.WithConfigFileNames("app.toml"),
+//		orale.WithoutFlags(),
+//	)
+func Load(applicationName string, opts ...Option) (*Loader, error) {
 	workingDir, err := os.Getwd()
 	if err != nil {
 		return nil, err
 	}
 
+	configName := configNameFromApplicationName(applicationName)
+	options := &loadOptions{
+		args:      os.Args[1:],
+		environ:   os.Environ(),
+		envPrefix: envPrefixFromApplicationName(applicationName),
+		configFileNames: []string{
+			fmt.Sprintf("%s.config.toml", configName),
+			fmt.Sprintf("%s.config.yaml", configName),
+			fmt.Sprintf("%s.config.yml", configName),
+			fmt.Sprintf("%s.config.json", configName),
+		},
+		searchPaths:          []string{workingDir},
+		applicationName:      configName,
+		systemSearchPaths:    true,
+		configPathKey:        "config",
+		profileKey:           "profile",
+		dotenvFileNames:      []string{".env"},
+		credentialsDirectory: true,
+		decryptionKeyPath:    "config_key",
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return load(options)
+}
+
+// LoadFromValues works like Load, but allows the caller to specify configuration
+// such as flag and environment values, as well as which path to start searching
+// for configuration files and which configuration file names to look for.
+func LoadFromValues(programArgs []string, envVarPrefix string, envVars []string, configSearchStartPath string, configFileNames []string) (*Loader, error) {
+	return load(&loadOptions{
+		args:            programArgs,
+		environ:         envVars,
+		envPrefix:       envVarPrefix,
+		configFileNames: configFileNames,
+		searchPaths:     []string{configSearchStartPath},
+	})
+}
+
+func load(options *loadOptions) (*Loader, error) {
+	flagArgs := []string{}
+	if !options.withoutFlags {
+		flagArgs = options.args
+	}
+	flagValues := loadFlags(flagArgs)
+	environmentValues := loadEnvironment(options.envPrefix, options.environ)
+	explicitConfigPaths := takeExplicitConfigPaths(options.configPathKey, flagValues, environmentValues)
+	if profiles := takeProfiles(options.profileKey, flagValues, environmentValues); len(profiles) != 0 {
+		options.profiles = profiles
+	}
+	environmentFileReferences := map[string]string{}
+	if options.fileReferences {
+		var err error
+		environmentFileReferences, err = resolveFileReferences(environmentValues, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	decryptionKey, err := takeDecryptionKey(options.decryptionKeyPath, environmentValues, "")
+	if err != nil {
+		return nil, err
+	}
+
+	configurationFiles := []*File{}
+	loadedFilePaths := map[string]bool{}
+	addConfigurationFiles := func(files []*File) {
+		for _, file := range files {
+			if loadedFilePaths[file.Path] {
+				continue
+			}
+			loadedFilePaths[file.Path] = true
+			configurationFiles = append(configurationFiles, file)
+		}
+	}
+	directorySources, err := loadDirectorySources(options)
+	if err != nil {
+		return nil, err
+	}
+	dotenvFiles, err := loadDotenvFiles(options)
+	if err != nil {
+		return nil, err
+	}
+
+	explicitFiles, err := loadExplicitConfigurationFiles(explicitConfigPaths, !options.withoutIncludes)
+	if err != nil {
+		return nil, err
+	}
+	addConfigurationFiles(explicitFiles)
+
+	searchPaths, systemSearchPaths := options.searchPaths, options.systemSearchPaths
+	if len(explicitFiles) != 0 && options.configReplacesSearch {
+		searchPaths, systemSearchPaths = nil, false
+	}
+	for _, searchPath := range searchPaths {
+		searchPathFiles, err := loadConfigurationFiles(searchPath, options.configFileNames, options.profiles, !options.withoutIncludes, options.isSearchBoundary)
+		if err != nil {
+			return nil, err
+		}
+		addConfigurationFiles(searchPathFiles)
+	}
+	if systemSearchPaths {
+		systemFiles, err := loadSystemConfigurationFiles(options)
+		if err != nil {
+			return nil, err
+		}
+		addConfigurationFiles(systemFiles)
+	}
+
+	loader := &Loader{
+		FlagValues:           flagValues,
+		EnvironmentValues:    environmentValues,
+		DotenvFiles:          dotenvFiles,
+		ConfigurationFiles:   configurationFiles,
+		envVarPrefix:         options.envPrefix,
+		flagLocations:        flagLocations(flagArgs),
+		environmentLocations: environmentLocations(options.envPrefix, options.environ, environmentFileReferences),
+		directorySources:     directorySources,
+	}
+
+	for _, dotenvFile := range dotenvFiles {
+		dotenvKey, err := takeDecryptionKey(options.decryptionKeyPath, dotenvFile.Values, filepath.Dir(dotenvFile.Path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", dotenvFile.Path, err)
+		}
+		if decryptionKey == "" {
+			decryptionKey = dotenvKey
+		}
+	}
+	if decryptionKey == "" {
+		decryptionKey, err = loadDecryptionKey(options)
+		if err != nil {
+			return nil, err
+		}
+	}
+	decryptedPaths, err := decryptSourceValues(loader.BuiltinSources(), decryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	loader.markSensitive(decryptedPaths...)
+	for targetPath := range environmentFileReferences {
+		loader.markSensitive(targetPath)
+	}
+	for _, dotenvFile := range dotenvFiles {
+		for path := range dotenvFile.Values {
+			if _, ok := dotenvFile.Values[path+fileReferenceSuffix]; ok && options.fileReferences {
+				loader.markSensitive(path)
+			}
+		}
+	}
+	for _, directorySource := range directorySources {
+		for path := range directorySource.FlatValues() {
+			loader.markSensitive(path)
+		}
+	}
+
+	return loader, nil
+}
+
+// envPrefixFromApplicationName converts an application name such as my-app or
+// myApp into an environment variable prefix such as MYAPP or MY_APP.
+func envPrefixFromApplicationName(applicationName string) string {
 	applicationNameRunes := []rune(applicationName)
 
 	envPrefixRunes := []rune{}
@@ -41,7 +263,13 @@ func Load(applicationName string) (*Loader, error) {
 			envPrefixRunes = append(envPrefixRunes, currentChar)
 		}
 	}
-	envPrefix := string(envPrefixRunes)
+	return string(envPrefixRunes)
+}
+
+// configNameFromApplicationName converts an application name such as myApp
+// into the kebab case name used for configuration files, such as my-app.
+func configNameFromApplicationName(applicationName string) string {
+	applicationNameRunes := []rune(applicationName)
 
 	configNameRunes := []rune{}
 	for i := 0; i < len(applicationNameRunes); i += 1 {
@@ -62,74 +290,18 @@ func Load(applicationName string) (*Loader, error) {
 			}
 		}
 	}
-	configName := string(configNameRunes)
-	configName = fmt.Sprintf("%s.config.toml", configName)
-
-	return LoadFromValues(
-		os.Args[1:],
-		envPrefix,
-		os.Environ(),
-		workingDir,
-		[]string{configName},
-	)
-}
-
-// LoadFromValues works like Load, but allows the caller to specify configuration
-// such as flag and environment values, as well as which path to start searching
-// for configuration files and which configuration file names to look for.
-func LoadFromValues(programArgs []string, envVarPrefix string, envVars []string, configSearchStartPath string, configFileNames []string) (*Loader, error) {
-	flagValues := loadFlags(programArgs)
-	environmentValues := loadEnvironment(envVarPrefix, envVars)
-	configurationFiles, err := loadConfigurationFiles(configSearchStartPath, configFileNames)
-	if err != nil {
-		return nil, err
-	}
-
-	return &Loader{
-		FlagValues:         flagValues,
-		EnvironmentValues:  environmentValues,
-		ConfigurationFiles: configurationFiles,
-	}, nil
+	return string(configNameRunes)
 }
 
 // NOTE: programArgs should not include the program name - os.Args[1:]
 // would be appropriate
 func loadFlags(programArgs []string) map[string][]any {
 	flagValues := map[string][]any{}
-	// short flags
 	for _, arg := range programArgs {
-		isShortFlag := arg[0] == '-' && arg[1] != '-'
-		isFlag := !isShortFlag && arg[0:2] == "--"
-
-		var startIndex int
-		switch {
-		case isShortFlag:
-			startIndex = 1
-		case isFlag:
-			startIndex = 2
-		default:
-			continue
-		}
-
-		splitIndex := -1
-		for i := startIndex; i < len(arg); i += 1 {
-			if arg[i] == '=' {
-				splitIndex = i
-				break
-			}
-		}
-		if splitIndex == -1 {
+		key, value, ok := parseFlag(arg)
+		if !ok {
 			continue
 		}
-
-		key := arg[startIndex:splitIndex]
-		value := arg[splitIndex+1:]
-
-		key = strings.ToLower(key)
-		key = strings.Replace(key, ".", "\\.", -1)
-		key = strings.Replace(key, "--", ".", -1)
-		key = strings.Replace(key, "-", "_", -1)
-
 		if _, ok := flagValues[key]; !ok {
 			flagValues[key] = []any{}
 		}
@@ -139,56 +311,161 @@ func loadFlags(programArgs []string) map[string][]any {
 	return flagValues
 }
 
+// parseFlag splits a flag such as --server--port=8080 into its path and
+// value. The boolean result is false if the argument is not a flag with a
+// value.
+func parseFlag(arg string) (string, string, bool) {
+	if len(arg) < 2 {
+		return "", "", false
+	}
+	// short flags
+	isShortFlag := arg[0] == '-' && arg[1] != '-'
+	isFlag := !isShortFlag && arg[0:2] == "--"
+
+	var startIndex int
+	switch {
+	case isShortFlag:
+		startIndex = 1
+	case isFlag:
+		startIndex = 2
+	default:
+		return "", "", false
+	}
+
+	splitIndex := -1
+	for i := startIndex; i < len(arg); i += 1 {
+		if arg[i] == '=' {
+			splitIndex = i
+			break
+		}
+	}
+	if splitIndex == -1 {
+		return "", "", false
+	}
+
+	key := arg[startIndex:splitIndex]
+	value := arg[splitIndex+1:]
+
+	key = strings.ToLower(key)
+	key = strings.Replace(key, ".", "\\.", -1)
+	key = strings.Replace(key, "--", ".", -1)
+	key = strings.Replace(key, "-", "_", -1)
+
+	return key, value, true
+}
+
 // NOTE: envVariables should be in the same format as the returned value from
 // os.Environ()
 func loadEnvironment(variablePrefix string, envVariables []string) map[string][]any {
-	variablePrefix += "__"
 	environmentValues := map[string][]any{}
 
 	for _, envVariable := range envVariables {
-		if len(envVariable) >= len(variablePrefix) && envVariable[0:len(variablePrefix)] == variablePrefix {
-			splitIndex := -1
-			for j := len(variablePrefix); j < len(envVariable); j += 1 {
-				if envVariable[j] == '=' {
-					splitIndex = j
-					break
-				}
-			}
-			if splitIndex == -1 {
-				continue
-			}
+		key, value, ok := parseEnvironmentVariable(variablePrefix, envVariable)
+		if !ok {
+			continue
+		}
+		if _, ok := environmentValues[key]; !ok {
+			environmentValues[key] = []any{}
+		}
+		environmentValues[key] = append(environmentValues[key], value)
+	}
+
+	return environmentValues
+}
+
+// parseEnvironmentVariable splits an environment variable such as
+// MY_APP__SERVER__PORT=8080 into its path and value. The boolean result is
+// false if the variable does not have the prefix.
+func parseEnvironmentVariable(variablePrefix string, envVariable string) (string, string, bool) {
+	variablePrefix += "__"
+	if len(envVariable) < len(variablePrefix) || envVariable[0:len(variablePrefix)] != variablePrefix {
+		return "", "", false
+	}
+	splitIndex := -1
+	for j := len(variablePrefix); j < len(envVariable); j += 1 {
+		if envVariable[j] == '=' {
+			splitIndex = j
+			break
+		}
+	}
+	if splitIndex == -1 {
+		return "", "", false
+	}
 
-			key := envVariable[len(variablePrefix):splitIndex]
-			value := envVariable[splitIndex+1:]
+	key := envVariable[len(variablePrefix):splitIndex]
+	value := envVariable[splitIndex+1:]
 
-			key = strings.ToLower(key)
-			key = strings.Replace(key, ".", "\\.", -1)
-			key = strings.Replace(key, "__", ".", -1)
+	key = strings.ToLower(key)
+	key = strings.Replace(key, ".", "\\.", -1)
+	key = strings.Replace(key, "__", ".", -1)
 
-			if _, ok := environmentValues[key]; !ok {
-				environmentValues[key] = []any{}
-			}
-			environmentValues[key] = append(environmentValues[key], value)
+	return key, value, true
+}
+
+// loadConfigurationFiles searches the start path and each of its parents for
+// configuration files and the overlays of the given profiles. The search stops
+// after the first directory for which isBoundary returns true. isBoundary may
+// be nil. If includes is false, include and extends directives are left as
+// ordinary values.
+func loadConfigurationFiles(startPath string, configNames, profiles []string, includes bool, isBoundary func(dirPath string) bool) ([]*File, error) {
+	configFiles := []*File{}
+	for _, dirPath := range searchPathDirs(startPath, isBoundary) {
+		dirConfigFiles, err := loadConfigurationFilesFromDir(dirPath, configNames, profiles, includes)
+		if err != nil {
+			return nil, err
 		}
+		configFiles = append(configFiles, dirConfigFiles...)
 	}
 
-	return environmentValues
+	return configFiles, nil
 }
 
-func loadConfigurationFiles(startPath string, configNames []string) ([]*File, error) {
+// searchPathDirs returns the start path followed by each of its parents. The
+// directories stop after the first for which isBoundary returns true.
+// isBoundary may be nil.
+func searchPathDirs(startPath string, isBoundary func(dirPath string) bool) []string {
 	currentPathChunks := strings.Split(startPath, string(filepath.Separator))
 
-	configFiles := []*File{}
+	dirPaths := []string{}
 	for i := len(currentPathChunks); i > 0; i -= 1 {
 		isAbsPath := currentPathChunks[0] == ""
 		currentPath := filepath.Join(currentPathChunks[:i]...)
 		if isAbsPath {
 			currentPath = string(filepath.Separator) + currentPath
 		}
+		dirPaths = append(dirPaths, currentPath)
+
+		if isBoundary != nil && isBoundary(currentPath) {
+			break
+		}
+	}
+
+	return dirPaths
+}
+
+// loadConfigurationFilesFromDir loads the configuration files with the given
+// names found directly within the directory, along with the fragments in
+// their drop-in directories. Fragments take precedence over the files they
+// sit beside. The overlays of each profile take precedence over the base
+// files, with later profiles taking precedence over earlier ones.
+func loadConfigurationFilesFromDir(dirPath string, configNames, profiles []string, includes bool) ([]*File, error) {
+	configNameGroups := [][]string{}
+	for i := len(profiles) - 1; i >= 0; i -= 1 {
+		configNameGroups = append(configNameGroups, profileConfigNames(configNames, profiles[i]))
+	}
+	configNameGroups = append(configNameGroups, configNames)
+
+	configFiles := []*File{}
+	for _, groupConfigNames := range configNameGroups {
+		dropInFiles, err := loadDropInFiles(dirPath, groupConfigNames, includes)
+		if err != nil {
+			return nil, err
+		}
+		configFiles = append(configFiles, dropInFiles...)
 
-		for _, configName := range configNames {
-			maybeConfigFilePath := filepath.Join(currentPath, configName)
-			maybeConfigFile, err := maybeLoadFile(maybeConfigFilePath)
+		for _, configName := range groupConfigNames {
+			maybeConfigFilePath := filepath.Join(dirPath, configName)
+			maybeConfigFile, err := maybeLoadFile(maybeConfigFilePath, includes)
 			if err != nil {
 				return nil, err
 			}
@@ -3,6 +3,7 @@ package orale_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/RobertWHurst/orale"
@@ -184,6 +185,144 @@ func TestLoadFromValues(t *testing.T) {
 		}
 	})
 
+	t.Run("should load yaml configuration files", func(t *testing.T) {
+		t.Parallel()
+
+		conf, err := orale.LoadFromValues([]string{}, "", []string{}, testAssetsPath, []string{"test-config-4.yaml"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(conf.ConfigurationFiles) != 1 {
+			t.Fatalf("expected 1 configuration file, got %d", len(conf.ConfigurationFiles))
+		}
+		file := conf.ConfigurationFiles[0]
+		if file.Format != orale.FormatYAML {
+			t.Fatalf("expected format to be yaml, got %s", file.Format)
+		}
+		if file.Values["port"][0] != int64(8080) {
+			t.Fatalf("expected port to be 8080, got %v", file.Values["port"])
+		}
+		if file.Values["database.connection_uri"][0] != "protocol://" {
+			t.Fatalf("expected database.connection_uri to be protocol://, got %v", file.Values["database.connection_uri"])
+		}
+		if file.Values["tags[1]"][0] != "b" {
+			t.Fatalf("expected tags[1] to be b, got %v", file.Values["tags[1]"])
+		}
+		if file.Values["channels[1].name"][0] != "News" {
+			t.Fatalf("expected channels[1].name to be News, got %v", file.Values["channels[1].name"])
+		}
+
+		type TestConfig struct {
+			Name     string   `config:"name"`
+			Port     uint16   `config:"port"`
+			Ratio    float32  `config:"ratio"`
+			Enabled  bool     `config:"enabled"`
+			Tags     []string `config:"tags"`
+			Channels []struct {
+				Name string `config:"name"`
+				Id   int    `config:"id"`
+			} `config:"channels"`
+		}
+
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.Name != "orale" || testConf.Port != 8080 || testConf.Ratio != 0.5 || !testConf.Enabled {
+			t.Fatalf("expected scalar values to be loaded, got %+v", testConf)
+		}
+		if len(testConf.Tags) != 2 || testConf.Tags[0] != "a" {
+			t.Fatalf("expected Tags to be [a b], got %v", testConf.Tags)
+		}
+		if len(testConf.Channels) != 2 || testConf.Channels[1].Id != 2 {
+			t.Fatalf("expected Channels to have 2 values, got %+v", testConf.Channels)
+		}
+	})
+
+	t.Run("should load json configuration files", func(t *testing.T) {
+		t.Parallel()
+
+		conf, err := orale.LoadFromValues([]string{}, "", []string{}, testAssetsPath, []string{"test-config-5.json"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(conf.ConfigurationFiles) != 1 {
+			t.Fatalf("expected 1 configuration file, got %d", len(conf.ConfigurationFiles))
+		}
+		file := conf.ConfigurationFiles[0]
+		if file.Format != orale.FormatJSON {
+			t.Fatalf("expected format to be json, got %s", file.Format)
+		}
+		if file.Values["channels[1].name"][0] != "News" {
+			t.Fatalf("expected channels[1].name to be News, got %v", file.Values["channels[1].name"])
+		}
+
+		type TestConfig struct {
+			Name     string  `config:"name"`
+			Big      uint64  `config:"big"`
+			Precise  int64   `config:"precise"`
+			Ratio    float64 `config:"ratio"`
+			Channels []struct {
+				Name string `config:"name"`
+				Id   int    `config:"id"`
+			} `config:"channels"`
+		}
+
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.Name != "orale" {
+			t.Fatalf("expected Name to be orale, got %s", testConf.Name)
+		}
+		if testConf.Big != 18446744073709551615 {
+			t.Fatalf("expected Big to be 18446744073709551615, got %d", testConf.Big)
+		}
+		if testConf.Precise != 9007199254740993 {
+			t.Fatalf("expected Precise to be 9007199254740993, got %d", testConf.Precise)
+		}
+		if testConf.Ratio != 0.25 {
+			t.Fatalf("expected Ratio to be 0.25, got %f", testConf.Ratio)
+		}
+		if len(testConf.Channels) != 2 || testConf.Channels[1].Id != 2 {
+			t.Fatalf("expected Channels to have 2 values, got %+v", testConf.Channels)
+		}
+	})
+
+	t.Run("should treat null values as unset", func(t *testing.T) {
+		t.Parallel()
+
+		for fileName, contents := range map[string]string{
+			"test-app.config.yaml": "name:\ndescription: ~\nhosts:\n  - a\n  - ~\n",
+			"test-app.config.json": `{"name": null, "description": null, "hosts": ["a", null]}`,
+		} {
+			tempDir := t.TempDir()
+			writeTestFile(t, filepath.Join(tempDir, fileName), contents)
+			conf, err := orale.LoadFromValues([]string{}, "", []string{}, tempDir, []string{fileName})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			type TestConfig struct {
+				Name        string   `config:"name" default:"orale"`
+				Description string   `config:"description"`
+				Hosts       []string `config:"hosts"`
+			}
+			testConf := TestConfig{}
+			if err := conf.Get("", &testConf); err != nil {
+				t.Fatalf("expected %s to load, got %s", fileName, err)
+			}
+			if testConf.Name != "orale" || testConf.Description != "" {
+				t.Fatalf("expected the null values of %s to be unset, got %+v", fileName, testConf)
+			}
+			if len(testConf.Hosts) == 0 || testConf.Hosts[0] != "a" {
+				t.Fatalf("expected the hosts of %s to start with a, got %v", fileName, testConf.Hosts)
+			}
+		}
+	})
+
 	t.Run("should handle multi entry values", func(t *testing.T) {
 		t.Parallel()
 
@@ -224,3 +363,820 @@ func TestLoadFromValues(t *testing.T) {
 		}
 	})
 }
+
+func TestLoadOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should load using the given options", func(t *testing.T) {
+		t.Parallel()
+
+		conf, err := orale.Load("test-application",
+			orale.WithArgs([]string{"--flag=value"}),
+			orale.WithEnviron([]string{"LEGACY__ENV=value", "TESTAPPLICATION__OTHER=value"}),
+			orale.WithEnvPrefix("LEGACY"),
+			orale.WithSearchPaths(filepath.Join(testAssetsPath, "search-dir")),
+			orale.WithConfigFileNames("test-config-1.toml", "test-config-2.toml"),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if conf.FlagValues["flag"][0] != "value" {
+			t.Fatalf("expected flag to be value, got %v", conf.FlagValues["flag"])
+		}
+		if len(conf.EnvironmentValues) != 1 {
+			t.Fatalf("expected 1 environment value, got %d", len(conf.EnvironmentValues))
+		}
+		if conf.EnvironmentValues["env"][0] != "value" {
+			t.Fatalf("expected env to be value, got %v", conf.EnvironmentValues["env"])
+		}
+		if len(conf.ConfigurationFiles) != 2 {
+			t.Fatalf("expected 2 configuration files, got %d", len(conf.ConfigurationFiles))
+		}
+		if conf.ConfigurationFiles[0].Path != filepath.Join(testAssetsPath, "search-dir/test-config-1.toml") {
+			t.Fatalf("expected first configuration file to be test-config-1.toml, got %s", conf.ConfigurationFiles[0].Path)
+		}
+	})
+
+	t.Run("should not load flags when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		conf, err := orale.Load("test-application",
+			orale.WithArgs([]string{"--flag=value"}),
+			orale.WithEnviron([]string{}),
+			orale.WithSearchPaths(testAssetsPath),
+			orale.WithoutFlags(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(conf.FlagValues) != 0 {
+			t.Fatalf("expected no flag values, got %d", len(conf.FlagValues))
+		}
+	})
+
+	t.Run("should not load the same file twice from overlapping search paths", func(t *testing.T) {
+		t.Parallel()
+
+		conf, err := orale.Load("test-application",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{}),
+			orale.WithSearchPaths(filepath.Join(testAssetsPath, "search-dir"), testAssetsPath),
+			orale.WithConfigFileNames("test-config-2.toml"),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(conf.ConfigurationFiles) != 1 {
+			t.Fatalf("expected 1 configuration file, got %d", len(conf.ConfigurationFiles))
+		}
+	})
+}
+
+func writeTestFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadSystemSearchPaths(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should load configuration files from the user configuration directories", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		homeDir := filepath.Join(tempDir, "home")
+		configHomeDir := filepath.Join(tempDir, "xdg")
+		workDir := filepath.Join(tempDir, "work")
+
+		writeTestFile(t, filepath.Join(workDir, "test-app.config.toml"), `a="work"`)
+		writeTestFile(t, filepath.Join(configHomeDir, "test-app", "config.toml"), `a="xdg"`+"\n"+`b="xdg"`)
+		writeTestFile(t, filepath.Join(homeDir, ".config", "test-app", "test-app.config.yaml"), "a: config\nb: config\nc: config\n")
+		writeTestFile(t, filepath.Join(homeDir, ".test-app.config.toml"), `a="home"`+"\n"+`d="home"`)
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{"HOME=" + homeDir, "XDG_CONFIG_HOME=" + configHomeDir}),
+			orale.WithSearchPaths(workDir),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		type TestConfig struct {
+			A string `config:"a"`
+			B string `config:"b"`
+			C string `config:"c"`
+			D string `config:"d"`
+		}
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+
+		if testConf.A != "work" {
+			t.Fatalf("expected A to be work, got %s", testConf.A)
+		}
+		if testConf.B != "xdg" {
+			t.Fatalf("expected B to be xdg, got %s", testConf.B)
+		}
+		if testConf.C != "config" {
+			t.Fatalf("expected C to be config, got %s", testConf.C)
+		}
+		if testConf.D != "home" {
+			t.Fatalf("expected D to be home, got %s", testConf.D)
+		}
+	})
+
+	t.Run("should stop searching at boundary markers and the home directory", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		projectDir := filepath.Join(tempDir, "project")
+		startDir := filepath.Join(projectDir, "cmd", "app")
+
+		writeTestFile(t, filepath.Join(tempDir, "test-app.config.toml"), `a="outside"`)
+		writeTestFile(t, filepath.Join(projectDir, "test-app.config.toml"), `a="project"`)
+		if err := os.MkdirAll(filepath.Join(projectDir, ".git"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(startDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{}),
+			orale.WithSearchPaths(startDir),
+			orale.WithBoundaryMarkers(".git"),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conf.ConfigurationFiles) != 1 {
+			t.Fatalf("expected 1 configuration file, got %d", len(conf.ConfigurationFiles))
+		}
+		if conf.ConfigurationFiles[0].Path != filepath.Join(projectDir, "test-app.config.toml") {
+			t.Fatalf("expected the project configuration file, got %s", conf.ConfigurationFiles[0].Path)
+		}
+
+		conf, err = orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{"HOME=" + projectDir}),
+			orale.WithSearchPaths(startDir),
+			orale.WithHomeBoundary(),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conf.ConfigurationFiles) != 1 {
+			t.Fatalf("expected 1 configuration file, got %d", len(conf.ConfigurationFiles))
+		}
+	})
+}
+
+func TestLoadExplicitConfig(t *testing.T) {
+	t.Parallel()
+
+	type TestConfig struct {
+		A string `config:"a"`
+		B string `config:"b"`
+		C string `config:"c"`
+	}
+
+	t.Run("should load files given by the config flag on top of searched files", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		workDir := filepath.Join(tempDir, "work")
+		writeTestFile(t, filepath.Join(workDir, "test-app.config.toml"), `a="work"`+"\n"+`b="work"`+"\n"+`c="work"`)
+		writeTestFile(t, filepath.Join(tempDir, "prod.toml"), `a="prod"`+"\n"+`b="prod"`)
+		writeTestFile(t, filepath.Join(tempDir, "override.toml"), `a="override"`)
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{
+				"--config=" + filepath.Join(tempDir, "prod.toml"),
+				"--config=" + filepath.Join(tempDir, "override.toml"),
+			}),
+			orale.WithEnviron([]string{"TESTAPP__CONFIG=" + filepath.Join(tempDir, "ignored.toml")}),
+			orale.WithSearchPaths(workDir),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := conf.FlagValues["config"]; ok {
+			t.Fatal("expected the config flag to be reserved")
+		}
+
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.A != "override" || testConf.B != "prod" || testConf.C != "work" {
+			t.Fatalf("expected A, B and C to be override, prod and work, got %+v", testConf)
+		}
+	})
+
+	t.Run("should load files given by the config environment variable in place of searched files", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		workDir := filepath.Join(tempDir, "work")
+		writeTestFile(t, filepath.Join(workDir, "test-app.config.toml"), `c="work"`)
+		writeTestFile(t, filepath.Join(tempDir, "prod.toml"), `a="prod"`+"\n"+`b="prod"`)
+		writeTestFile(t, filepath.Join(tempDir, "override.toml"), `a="override"`)
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{
+				"TESTAPP__CONFIG=" + filepath.Join(tempDir, "prod.toml") + string(os.PathListSeparator) + filepath.Join(tempDir, "override.toml"),
+			}),
+			orale.WithSearchPaths(workDir),
+			orale.WithoutSystemSearchPaths(),
+			orale.WithConfigReplacingSearch(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.A != "override" || testConf.B != "prod" || testConf.C != "" {
+			t.Fatalf("expected A, B and C to be override, prod and empty, got %+v", testConf)
+		}
+	})
+
+	t.Run("should return an error when a named file does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := orale.Load("test-app",
+			orale.WithArgs([]string{"--config=" + filepath.Join(t.TempDir(), "missing.toml")}),
+			orale.WithEnviron([]string{}),
+			orale.WithSearchPaths(t.TempDir()),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestLoadIncludes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should merge included and extended files beneath the including file", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		serviceDir := filepath.Join(tempDir, "service")
+		writeTestFile(t, filepath.Join(tempDir, "shared.config.toml"), "[db]\nhost=\"shared\"\nport=5432\nname=\"shared\"\n")
+		writeTestFile(t, filepath.Join(serviceDir, "base.toml"), "[db]\nname=\"base\"\nuser=\"base\"\n")
+		writeTestFile(t, filepath.Join(serviceDir, "secrets", "a.toml"), "[db]\npassword=\"a\"\n")
+		writeTestFile(t, filepath.Join(serviceDir, "secrets", "b.toml"), "[db]\npassword=\"b\"\n")
+		writeTestFile(t, filepath.Join(serviceDir, "test-app.config.toml"), strings.Join([]string{
+			`extends = "../shared.config.toml"`,
+			`include = ["base.toml", "secrets/*.toml"]`,
+			`[db]`,
+			`user = "service"`,
+		}, "\n"))
+
+		conf, err := orale.LoadFromValues([]string{}, "", []string{}, serviceDir, []string{"test-app.config.toml"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conf.ConfigurationFiles) == 0 {
+			t.Fatal("expected a configuration file")
+		}
+		file := conf.ConfigurationFiles[0]
+		if len(file.Includes) != 4 {
+			t.Fatalf("expected 4 included files, got %v", file.Includes)
+		}
+		if _, ok := file.Values["include[0]"]; ok {
+			t.Fatal("expected the include directive to be removed from the values")
+		}
+
+		type TestConfig struct {
+			Db struct {
+				Host     string `config:"host"`
+				Port     int    `config:"port"`
+				Name     string `config:"name"`
+				User     string `config:"user"`
+				Password string `config:"password"`
+			} `config:"db"`
+		}
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.Db.Host != "shared" || testConf.Db.Port != 5432 {
+			t.Fatalf("expected extended values to be merged, got %+v", testConf.Db)
+		}
+		if testConf.Db.Name != "base" {
+			t.Fatalf("expected Db.Name to be base, got %s", testConf.Db.Name)
+		}
+		if testConf.Db.User != "service" {
+			t.Fatalf("expected Db.User to be service, got %s", testConf.Db.User)
+		}
+		if testConf.Db.Password != "b" {
+			t.Fatalf("expected Db.Password to be b, got %s", testConf.Db.Password)
+		}
+	})
+
+	t.Run("should return an error on include cycles", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tempDir, "test-app.config.toml"), `include = ["a.toml"]`)
+		writeTestFile(t, filepath.Join(tempDir, "a.toml"), `include = ["b.toml"]`)
+		writeTestFile(t, filepath.Join(tempDir, "b.toml"), `extends = "test-app.config.toml"`)
+
+		_, err := orale.LoadFromValues([]string{}, "", []string{}, tempDir, []string{"test-app.config.toml"})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !strings.Contains(err.Error(), "cycle") {
+			t.Fatalf("expected a cycle error, got %s", err)
+		}
+	})
+
+	t.Run("should return an error when an included file does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tempDir, "test-app.config.toml"), `include = ["missing.toml"]`)
+
+		_, err := orale.LoadFromValues([]string{}, "", []string{}, tempDir, []string{"test-app.config.toml"})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("should only include glob matches that are configuration files", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tempDir, "src", "main.go"), "package main\n")
+		writeTestFile(t, filepath.Join(tempDir, "src", "extra.toml"), `name = "extra"`)
+		writeTestFile(t, filepath.Join(tempDir, "test-app.config.toml"), `include = ["src/*"]`)
+
+		conf, err := orale.LoadFromValues([]string{}, "", []string{}, tempDir, []string{"test-app.config.toml"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if includes := conf.ConfigurationFiles[0].Includes; len(includes) != 1 || filepath.Base(includes[0]) != "extra.toml" {
+			t.Fatalf("expected only extra.toml to be included, got %v", includes)
+		}
+	})
+
+	t.Run("should load include keys as values with WithoutIncludes", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tempDir, "test-app.config.toml"), `include = ["src/*.go"]`+"\n"+`extends = "missing.toml"`)
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+			orale.WithoutIncludes(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		type TestConfig struct {
+			Include []string `config:"include"`
+			Extends string   `config:"extends"`
+		}
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if len(testConf.Include) != 1 || testConf.Include[0] != "src/*.go" {
+			t.Fatalf("expected Include to be [src/*.go], got %v", testConf.Include)
+		}
+		if testConf.Extends != "missing.toml" {
+			t.Fatalf("expected Extends to be missing.toml, got %s", testConf.Extends)
+		}
+	})
+}
+
+func TestLoadDropIns(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should load drop-in fragments in lexical order on top of the base file", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		dropInDir := filepath.Join(tempDir, "test-app.config.d")
+		writeTestFile(t, filepath.Join(tempDir, "test-app.config.toml"), "[db]\nhost=\"base\"\nport=5432\nname=\"base\"\n")
+		writeTestFile(t, filepath.Join(dropInDir, "10-host.toml"), "[db]\nhost=\"ten\"\nname=\"ten\"\n")
+		writeTestFile(t, filepath.Join(dropInDir, "20-name.toml"), "[db]\nname=\"twenty\"\n")
+		writeTestFile(t, filepath.Join(dropInDir, "README.md"), "not a fragment")
+
+		conf, err := orale.LoadFromValues([]string{}, "", []string{}, tempDir, []string{"test-app.config.toml"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conf.ConfigurationFiles) < 3 {
+			t.Fatalf("expected 3 configuration files, got %d", len(conf.ConfigurationFiles))
+		}
+		expectedPaths := []string{
+			filepath.Join(dropInDir, "20-name.toml"),
+			filepath.Join(dropInDir, "10-host.toml"),
+			filepath.Join(tempDir, "test-app.config.toml"),
+		}
+		for i, expectedPath := range expectedPaths {
+			if conf.ConfigurationFiles[i].Path != expectedPath {
+				t.Fatalf("expected file %d to be %s, got %s", i, expectedPath, conf.ConfigurationFiles[i].Path)
+			}
+		}
+
+		type TestConfig struct {
+			Db struct {
+				Host string `config:"host"`
+				Port int    `config:"port"`
+				Name string `config:"name"`
+			} `config:"db"`
+		}
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.Db.Host != "ten" || testConf.Db.Port != 5432 || testConf.Db.Name != "twenty" {
+			t.Fatalf("expected fragments to override the base file, got %+v", testConf.Db)
+		}
+	})
+}
+
+func TestLoadProfiles(t *testing.T) {
+	t.Parallel()
+
+	type TestConfig struct {
+		A string `config:"a"`
+		B string `config:"b"`
+		C string `config:"c"`
+	}
+
+	writeProfileFiles := func(t *testing.T) string {
+		tempDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tempDir, "test-app.config.toml"), `a="base"`+"\n"+`b="base"`+"\n"+`c="base"`)
+		writeTestFile(t, filepath.Join(tempDir, "test-app.production.config.toml"), `a="production"`+"\n"+`b="production"`)
+		writeTestFile(t, filepath.Join(tempDir, "test-app.eu-west.config.toml"), `a="eu-west"`)
+		return tempDir
+	}
+
+	t.Run("should layer stacked profile overlays on top of the base file", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := writeProfileFiles(t)
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{"--profile=production,eu-west"}),
+			orale.WithEnviron([]string{"TESTAPP__PROFILE=ignored"}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := conf.FlagValues["profile"]; ok {
+			t.Fatal("expected the profile flag to be reserved")
+		}
+
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.A != "eu-west" || testConf.B != "production" || testConf.C != "base" {
+			t.Fatalf("expected A, B and C to be eu-west, production and base, got %+v", testConf)
+		}
+	})
+
+	t.Run("should select profiles from the environment and options", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := writeProfileFiles(t)
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{"TESTAPP__PROFILE=production"}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+			orale.WithProfiles("eu-west"),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.A != "production" || testConf.B != "production" || testConf.C != "base" {
+			t.Fatalf("expected A, B and C to be production, production and base, got %+v", testConf)
+		}
+
+		conf, err = orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+			orale.WithProfiles("eu-west"),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testConf = TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.A != "eu-west" || testConf.B != "base" || testConf.C != "base" {
+			t.Fatalf("expected A, B and C to be eu-west, base and base, got %+v", testConf)
+		}
+	})
+}
+
+func TestLoadDotenv(t *testing.T) {
+	t.Parallel()
+
+	type TestConfig struct {
+		A string `config:"a"`
+		B string `config:"b"`
+		C string `config:"c"`
+		D string `config:"d"`
+		E string `config:"e"`
+		F string `config:"f"`
+		G string `config:"g"`
+	}
+
+	t.Run("should load dotenv files beneath environment variables", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tempDir, "test-app.config.toml"), `a="file"`+"\n"+`g="file"`)
+		writeTestFile(t, filepath.Join(tempDir, ".env"), strings.Join([]string{
+			`# local overrides`,
+			`TESTAPP__A=dotenv`,
+			`export TESTAPP__B = "double # not a comment\n"   # a comment`,
+			`TESTAPP__C='single \n'`,
+			`TESTAPP__D="first`,
+			`second"`,
+			`TESTAPP__E=unquoted value # a comment`,
+			`TESTAPP__F=dotenv`,
+			`OTHER_VAR=ignored`,
+		}, "\n"))
+		writeTestFile(t, filepath.Join(tempDir, ".env.local"), `TESTAPP__F=local`)
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{"TESTAPP__A=environment", "TESTAPP__PROFILE=local"}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conf.DotenvFiles) != 2 {
+			t.Fatalf("expected 2 dotenv files, got %d", len(conf.DotenvFiles))
+		}
+		if _, ok := conf.DotenvFiles[1].Values["other_var"]; ok {
+			t.Fatal("expected variables without the prefix to be ignored")
+		}
+
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		expectedConf := TestConfig{
+			A: "environment",
+			B: "double # not a comment\n",
+			C: `single \n`,
+			D: "first\nsecond",
+			E: "unquoted value",
+			F: "local",
+			G: "file",
+		}
+		if testConf != expectedConf {
+			t.Fatalf("expected %+v, got %+v", expectedConf, testConf)
+		}
+	})
+
+	t.Run("should return an error for unterminated quoted values", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tempDir, ".env"), `TESTAPP__A="unterminated`)
+
+		_, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("should skip malformed lines of variables without the prefix", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		workDir := filepath.Join(tempDir, "work")
+		writeTestFile(t, filepath.Join(tempDir, ".env"), strings.Join([]string{
+			`DEBUG`,
+			`COMPOSE_PROJECT_NAME="unterminated`,
+			`TESTAPP__A=parent`,
+			`OTHER='quoted' trailing`,
+			`TESTAPP__B=parent`,
+		}, "\n"))
+		writeTestFile(t, filepath.Join(workDir, ".env"), `TESTAPP__A=work`)
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{}),
+			orale.WithSearchPaths(workDir),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.A != "work" || testConf.B != "parent" {
+			t.Fatalf("expected A to be work and B to be parent, got %+v", testConf)
+		}
+	})
+
+	t.Run("should return an error for malformed lines of variables with the prefix", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tempDir, ".env"), "DEBUG\nTESTAPP__DEBUG\n")
+
+		_, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !strings.Contains(err.Error(), "line 2") {
+			t.Fatalf("expected the error to name line 2, got %s", err)
+		}
+	})
+}
+
+func TestLoadSecretFiles(t *testing.T) {
+	t.Parallel()
+
+	type TestConfig struct {
+		Db struct {
+			User     string `config:"user"`
+			Password string `config:"password"`
+			Host     string `config:"host"`
+		} `config:"db"`
+		Log struct {
+			OutputFile string `config:"output_file"`
+		} `config:"log"`
+		ApiKey string `config:"api_key"`
+		Token  string `config:"token"`
+	}
+
+	t.Run("should read the files referenced by environment variables ending in _FILE", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tempDir, "db-password"), "hunter2\n")
+		writeTestFile(t, filepath.Join(tempDir, "log.txt"), "existing log")
+
+		_, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{
+				"TESTAPP__DB__USER_FILE=" + filepath.Join(tempDir, "missing"),
+			}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+			orale.WithFileReferences(),
+		)
+		if err == nil {
+			t.Fatal("expected an error for a missing referenced file")
+		}
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{
+				"TESTAPP__DB__PASSWORD_FILE=" + filepath.Join(tempDir, "db-password"),
+				"TESTAPP__DB__USER=admin",
+				"TESTAPP__DB__USER_FILE=" + filepath.Join(tempDir, "missing"),
+				"TESTAPP__LOG__OUTPUT=stdout",
+				"TESTAPP__LOG__OUTPUT_FILE=" + filepath.Join(tempDir, "log.txt"),
+			}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+			orale.WithFileReferences(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.Db.Password != "hunter2" || testConf.Db.User != "admin" {
+			t.Fatalf("expected the password to be read from its file, got %+v", testConf.Db)
+		}
+		if testConf.Log.OutputFile != filepath.Join(tempDir, "log.txt") {
+			t.Fatalf("expected Log.OutputFile to keep its value, got %s", testConf.Log.OutputFile)
+		}
+	})
+
+	t.Run("should leave variables ending in _FILE alone by default", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tempDir, "db-password"), "hunter2\n")
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{
+				"TESTAPP__DB__PASSWORD_FILE=" + filepath.Join(tempDir, "db-password"),
+				"TESTAPP__LOG__OUTPUT_FILE=" + filepath.Join(tempDir, "app.log"),
+			}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.Db.Password != "" {
+			t.Fatalf("expected Db.Password to be unset, got %s", testConf.Db.Password)
+		}
+		if testConf.Log.OutputFile != filepath.Join(tempDir, "app.log") {
+			t.Fatalf("expected Log.OutputFile to keep its value, got %s", testConf.Log.OutputFile)
+		}
+	})
+
+	t.Run("should load values from secret directories", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		secretsDir := filepath.Join(tempDir, "secrets")
+		credentialsDir := filepath.Join(tempDir, "credentials")
+		writeTestFile(t, filepath.Join(secretsDir, "db__password"), "from-secrets\n")
+		writeTestFile(t, filepath.Join(secretsDir, "db", "host"), "db.internal")
+		writeTestFile(t, filepath.Join(secretsDir, "API_KEY"), "key")
+		writeTestFile(t, filepath.Join(secretsDir, "..data", "ignored"), "ignored")
+		writeTestFile(t, filepath.Join(credentialsDir, "db.password"), "from-credentials")
+		writeTestFile(t, filepath.Join(credentialsDir, "token"), "token")
+
+		conf, err := orale.Load("test-app",
+			orale.WithArgs([]string{}),
+			orale.WithEnviron([]string{
+				"CREDENTIALS_DIRECTORY=" + credentialsDir,
+				"TESTAPP__DB__USER=admin",
+			}),
+			orale.WithSearchPaths(tempDir),
+			orale.WithoutSystemSearchPaths(),
+			orale.WithSecretDirectories(secretsDir, filepath.Join(tempDir, "missing")),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.Db.Password != "from-secrets" || testConf.Db.Host != "db.internal" || testConf.Db.User != "admin" {
+			t.Fatalf("expected db values from the secret directories, got %+v", testConf.Db)
+		}
+		if testConf.ApiKey != "key" || testConf.Token != "token" {
+			t.Fatalf("expected ApiKey and Token to be key and token, got %s and %s", testConf.ApiKey, testConf.Token)
+		}
+	})
+}
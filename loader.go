@@ -1,5 +1,7 @@
 package orale
 
+import "reflect"
+
 // Loader is a struct that contains all the values loaded from flags, environment
 // variables, and configuration files. It can be used to marshal the values into
 // a struct.
@@ -8,6 +10,31 @@ type Loader struct {
 	FlagValues map[string][]any
 	// EnvironmentValues is a map of environment variable values by path.
 	EnvironmentValues map[string][]any
+	// DotenvFiles is a slice of dotenv files. Their values are mapped to paths
+	// in the same way as environment variables.
+	DotenvFiles []*File
 	// ConfigurationFiles is a slice of configuration files.
 	ConfigurationFiles []*File
+	// Sources is the ordered list of sources values are resolved from, highest
+	// precedence first. If Sources is nil, which it is for loaders created by
+	// Load and LoadFromValues, values are resolved from the sources returned by
+	// BuiltinSources, so changes to FlagValues, EnvironmentValues, DotenvFiles
+	// and ConfigurationFiles take effect. Once Sources is set those fields are
+	// no longer consulted.
+	Sources []Source
+
+	// envVarPrefix is the prefix environment variables were loaded with. It is
+	// used to describe missing values.
+	envVarPrefix string
+	// flagLocations and environmentLocations hold the flags and environment
+	// variables each value was loaded from, for Explain.
+	flagLocations        map[string][]string
+	environmentLocations map[string][]string
+	// directorySources holds the secret directories loaded by Load.
+	directorySources []Source
+	// sensitivePaths holds the paths whose values are redacted by Explain and
+	// Dump. It is only written by Load.
+	sensitivePaths map[string]bool
+	// secretTypes holds the configuration types given to WithSecretsOf.
+	secretTypes []reflect.Type
 }
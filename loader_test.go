@@ -28,7 +28,7 @@ func TestLoadAndGetConfig(t *testing.T) {
 			} `config:"server"`
 			Channels []struct {
 				Name string `config:"name"`
-				Id   int    `config:"id"`
+				Id   string `config:"id"`
 			} `config:"channels"`
 		}
 
@@ -50,5 +50,12 @@ func TestLoadAndGetConfig(t *testing.T) {
 		if err := loader.Get("", &testConfig); err != nil {
 			t.Fatal(err)
 		}
+
+		if testConfig.Server.Port != 8080 {
+			t.Fatalf("expected Server.Port to be 8080, got %d", testConfig.Server.Port)
+		}
+		if len(testConfig.Channels) != 4 {
+			t.Fatalf("expected Channels to have 4 values, got %d", len(testConfig.Channels))
+		}
 	})
 }
@@ -0,0 +1,380 @@
+package orale
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Locator can be implemented by a Source to describe where each of its values
+// was found, for example the name of a flag or environment variable, or the
+// file and line. It is used by Explain.
+type Locator interface {
+	Locate(path string) string
+}
+
+// locatedSource is a Source that remembers where each of its values was found.
+type locatedSource struct {
+	valuesSource
+	locations map[string][]string
+}
+
+func newLocatedSource(name string, values map[string][]any, locations map[string][]string) *locatedSource {
+	return &locatedSource{
+		valuesSource: valuesSource{name: name, values: values},
+		locations:    locations,
+	}
+}
+
+func (s *locatedSource) Locate(path string) string {
+	return strings.Join(s.locations[path], ", ")
+}
+
+// flagLocations locates each flag value by the name of the flag as it was
+// given, for example --server--port. The value is left out so secrets given as
+// flags are not revealed by Explain or Dump. Flags given more than once are
+// located once.
+func flagLocations(programArgs []string) map[string][]string {
+	locations := map[string][]string{}
+	for _, arg := range programArgs {
+		key, _, ok := parseFlag(arg)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(arg, "=")
+		if !slices.Contains(locations[key], name) {
+			locations[key] = append(locations[key], name)
+		}
+	}
+	return locations
+}
+
+// environmentLocations locates each environment value by the name of its
+// environment variable. Values read from files referenced by _FILE variables
+// are located by the referencing variable.
+func environmentLocations(variablePrefix string, envVariables []string, fileReferences map[string]string) map[string][]string {
+	locations := map[string][]string{}
+	for _, envVariable := range envVariables {
+		if key, _, ok := parseEnvironmentVariable(variablePrefix, envVariable); ok {
+			name, _, _ := strings.Cut(envVariable, "=")
+			locations[key] = append(locations[key], name)
+		}
+	}
+	for targetPath, referencePath := range fileReferences {
+		locations[targetPath] = locations[referencePath]
+	}
+	return locations
+}
+
+// Locate returns the path of the file, followed by the line the value is
+// found on if it is known. Values merged in from included files are located by
+// the including file alone.
+func (f *File) Locate(path string) string {
+	if !f.ownPaths[path] {
+		return f.Path
+	}
+	for linePath := path; linePath != ""; linePath = parentPath(linePath) {
+		if line, ok := f.lines[linePath]; ok {
+			return fmt.Sprintf("%s:%d", f.Path, line)
+		}
+	}
+	return f.Path
+}
+
+// parentPath returns the path containing the given path, for example a.b for
+// a.b.c and a for a[0].
+func parentPath(path string) string {
+	for i := len(path) - 1; i >= 0; i -= 1 {
+		if (path[i] == '.' || path[i] == '[') && (i == 0 || path[i-1] != '\\') {
+			return path[:i]
+		}
+	}
+	return ""
+}
+
+// fileLines returns the line each key and table in a file starts on by path.
+// Lines that cannot be determined are left out.
+func fileLines(format Format, fileBytes []byte) map[string]int {
+	switch format {
+	case FormatYAML:
+		return yamlLines(fileBytes)
+	case FormatJSON:
+		return jsonLines(fileBytes)
+	default:
+		return tomlLines(string(fileBytes))
+	}
+}
+
+// tomlLines scans a TOML document for table headers and keys. It does not
+// validate the document, which has already been decoded, so it only needs to
+// track tables, arrays of tables and values spanning several lines.
+func tomlLines(contents string) map[string]int {
+	lines := map[string]int{}
+	arrayIndexes := map[string]int{}
+	table := ""
+
+	rawLines := strings.Split(strings.ReplaceAll(contents, "\r\n", "\n"), "\n")
+	for i := 0; i < len(rawLines); i += 1 {
+		lineNumber := i + 1
+		line := strings.TrimSpace(rawLines[i])
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[[") {
+			endIndex := indexOutsideQuotes(line, ']')
+			if endIndex == -1 {
+				continue
+			}
+			keys := splitTOMLKey(line[2:endIndex])
+			if len(keys) == 0 {
+				continue
+			}
+			arrayPath := joinPath(resolveTOMLTablePath(keys[:len(keys)-1], arrayIndexes), keys[len(keys)-1])
+			index, ok := arrayIndexes[arrayPath]
+			if ok {
+				index += 1
+			}
+			arrayIndexes[arrayPath] = index
+			if _, ok := lines[arrayPath]; !ok {
+				lines[arrayPath] = lineNumber
+			}
+			table = fmt.Sprintf("%s[%d]", arrayPath, index)
+			lines[table] = lineNumber
+			continue
+		}
+
+		if line[0] == '[' {
+			endIndex := indexOutsideQuotes(line, ']')
+			if endIndex == -1 {
+				continue
+			}
+			table = resolveTOMLTablePath(splitTOMLKey(line[1:endIndex]), arrayIndexes)
+			if _, ok := lines[table]; !ok {
+				lines[table] = lineNumber
+			}
+			continue
+		}
+
+		keyEndIndex := indexOutsideQuotes(line, '=')
+		if keyEndIndex == -1 {
+			continue
+		}
+		keyPath := table
+		for _, key := range splitTOMLKey(line[:keyEndIndex]) {
+			keyPath = joinPath(keyPath, key)
+			if _, ok := lines[keyPath]; !ok {
+				lines[keyPath] = lineNumber
+			}
+		}
+		i = skipTOMLValue(rawLines, i, line[keyEndIndex+1:])
+	}
+
+	return lines
+}
+
+// resolveTOMLTablePath joins the keys of a table header into a path. Keys
+// naming an array of tables refer to its most recent entry.
+func resolveTOMLTablePath(keys []string, arrayIndexes map[string]int) string {
+	tablePath := ""
+	for _, key := range keys {
+		tablePath = joinPath(tablePath, key)
+		if index, ok := arrayIndexes[tablePath]; ok {
+			tablePath = fmt.Sprintf("%s[%d]", tablePath, index)
+		}
+	}
+	return tablePath
+}
+
+// splitTOMLKey splits a dotted TOML key such as a."b.c".d into its keys.
+func splitTOMLKey(dottedKey string) []string {
+	keys := []string{}
+	for dottedKey != "" {
+		dotIndex := indexOutsideQuotes(dottedKey, '.')
+		key := dottedKey
+		if dotIndex == -1 {
+			dottedKey = ""
+		} else {
+			key, dottedKey = dottedKey[:dotIndex], dottedKey[dotIndex+1:]
+		}
+		key = strings.TrimSpace(key)
+		switch {
+		case strings.HasPrefix(key, `"`):
+			if unquotedKey, err := strconv.Unquote(key); err == nil {
+				key = unquotedKey
+			}
+		case strings.HasPrefix(key, "'"):
+			key = strings.Trim(key, "'")
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// indexOutsideQuotes returns the index of the first occurrence of the byte
+// that is not within a quoted string, or -1 if there is none.
+func indexOutsideQuotes(str string, char byte) int {
+	for i := 0; i < len(str); i += 1 {
+		switch str[i] {
+		case char:
+			return i
+		case '"', '\'':
+			i = skipTOMLString(str, i)
+		}
+	}
+	return -1
+}
+
+// skipTOMLString returns the index of the quote closing the single line string
+// starting at the given index.
+func skipTOMLString(str string, startIndex int) int {
+	quote := str[startIndex]
+	i := startIndex + 1
+	for i < len(str) && str[i] != quote {
+		if quote == '"' && str[i] == '\\' {
+			i += 1
+		}
+		i += 1
+	}
+	return i
+}
+
+// skipTOMLValue returns the index of the last line of the value starting on
+// the given line. Values span several lines when they contain multi-line
+// strings, or arrays and inline tables left open at the end of a line.
+func skipTOMLValue(rawLines []string, i int, value string) int {
+	depth := 0
+	multiLineQuote := ""
+	for {
+		for j := 0; j < len(value); j += 1 {
+			if multiLineQuote != "" {
+				if strings.HasPrefix(value[j:], multiLineQuote) {
+					j += 2
+					multiLineQuote = ""
+				} else if multiLineQuote == `"""` && value[j] == '\\' {
+					j += 1
+				}
+				continue
+			}
+			switch {
+			case strings.HasPrefix(value[j:], `"""`), strings.HasPrefix(value[j:], `'''`):
+				multiLineQuote = value[j : j+3]
+				j += 2
+			case value[j] == '"', value[j] == '\'':
+				j = skipTOMLString(value, j)
+			case value[j] == '#':
+				j = len(value)
+			case value[j] == '[', value[j] == '{':
+				depth += 1
+			case value[j] == ']', value[j] == '}':
+				depth -= 1
+			}
+		}
+		if (depth <= 0 && multiLineQuote == "") || i+1 >= len(rawLines) {
+			return i
+		}
+		i += 1
+		value = rawLines[i]
+	}
+}
+
+// yamlLines walks a YAML document's nodes for the line of each key and
+// sequence entry.
+func yamlLines(fileBytes []byte) map[string]int {
+	lines := map[string]int{}
+	document := yaml.Node{}
+	if err := yaml.Unmarshal(fileBytes, &document); err != nil {
+		return lines
+	}
+	addYAMLLines(&document, "", lines)
+	return lines
+}
+
+func addYAMLLines(node *yaml.Node, currentPath string, lines map[string]int) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, contentNode := range node.Content {
+			addYAMLLines(contentNode, currentPath, lines)
+		}
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			addYAMLLines(node.Alias, currentPath, lines)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			keyPath := joinPath(currentPath, keyNode.Value)
+			lines[keyPath] = keyNode.Line
+			addYAMLLines(valueNode, keyPath, lines)
+		}
+	case yaml.SequenceNode:
+		for i, entryNode := range node.Content {
+			entryPath := fmt.Sprintf("%s[%d]", currentPath, i)
+			lines[entryPath] = entryNode.Line
+			addYAMLLines(entryNode, entryPath, lines)
+		}
+	}
+}
+
+// jsonLines walks a JSON document's tokens for the line of each key and array
+// entry.
+func jsonLines(fileBytes []byte) map[string]int {
+	lines := map[string]int{}
+	decoder := json.NewDecoder(bytes.NewReader(fileBytes))
+	_ = addJSONLines(decoder, fileBytes, "", lines)
+	return lines
+}
+
+func addJSONLines(decoder *json.Decoder, fileBytes []byte, currentPath string, lines map[string]int) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		for decoder.More() {
+			keyOffset := decoder.InputOffset()
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			keyPath := joinPath(currentPath, fmt.Sprint(keyToken))
+			lines[keyPath] = lineAtOffset(fileBytes, keyOffset)
+			if err := addJSONLines(decoder, fileBytes, keyPath, lines); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; decoder.More(); i += 1 {
+			entryPath := fmt.Sprintf("%s[%d]", currentPath, i)
+			lines[entryPath] = lineAtOffset(fileBytes, decoder.InputOffset())
+			if err := addJSONLines(decoder, fileBytes, entryPath, lines); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Consume the closing delimiter.
+	_, err = decoder.Token()
+	return err
+}
+
+// lineAtOffset returns the line of the first token at or after the offset.
+// Whitespace and the separators between tokens are skipped.
+func lineAtOffset(fileBytes []byte, offset int64) int {
+	i := int(offset)
+	for i < len(fileBytes) && strings.IndexByte(" \t\r\n,:", fileBytes[i]) != -1 {
+		i += 1
+	}
+	return bytes.Count(fileBytes[:i], []byte("\n")) + 1
+}
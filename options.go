@@ -0,0 +1,224 @@
+package orale
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Option configures how Load finds configuration values.
+type Option func(*loadOptions)
+
+type loadOptions struct {
+	args            []string
+	environ         []string
+	envPrefix       string
+	configFileNames []string
+	searchPaths     []string
+	withoutFlags    bool
+	withoutIncludes bool
+
+	applicationName   string
+	systemSearchPaths bool
+	boundaryMarkers   []string
+	homeBoundary      bool
+
+	configPathKey        string
+	configReplacesSearch bool
+
+	profileKey string
+	profiles   []string
+
+	dotenvFileNames []string
+
+	fileReferences       bool
+	secretDirs           []string
+	credentialsDirectory bool
+
+	decryptionKeyPath     string
+	decryptionKey         string
+	decryptionKeyFilePath string
+}
+
+// getenv returns the value of an environment variable from the environment
+// being loaded rather than the process environment.
+func (o *loadOptions) getenv(key string) string {
+	for _, envVariable := range o.environ {
+		if name, value, ok := strings.Cut(envVariable, "="); ok && name == key {
+			return value
+		}
+	}
+	return ""
+}
+
+// isSearchBoundary returns true if the upward search for configuration files
+// should stop at the directory.
+func (o *loadOptions) isSearchBoundary(dirPath string) bool {
+	if o.homeBoundary {
+		if homeDir := o.getenv("HOME"); homeDir != "" && filepath.Clean(homeDir) == filepath.Clean(dirPath) {
+			return true
+		}
+	}
+	for _, marker := range o.boundaryMarkers {
+		if _, err := os.Stat(filepath.Join(dirPath, marker)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// WithEnvPrefix sets the prefix environment variables must have to be loaded.
+// The prefix is separated from the path by a double underscore, so a prefix of
+// MY_APP loads variables such as MY_APP__DB__PORT. By default the prefix is
+// derived from the application name.
+func WithEnvPrefix(envPrefix string) Option {
+	return func(o *loadOptions) {
+		o.envPrefix = envPrefix
+	}
+}
+
+// WithConfigFileNames sets the configuration file names to search for,
+// replacing the names derived from the application name. Names given first
+// take precedence over names given later when found in the same directory.
+func WithConfigFileNames(configFileNames ...string) Option {
+	return func(o *loadOptions) {
+		o.configFileNames = configFileNames
+	}
+}
+
+// WithSearchPaths sets the directories to search for configuration files,
+// replacing the working directory. Each directory and its parents are
+// searched, with files found from earlier paths taking precedence.
+func WithSearchPaths(searchPaths ...string) Option {
+	return func(o *loadOptions) {
+		o.searchPaths = searchPaths
+	}
+}
+
+// WithArgs sets the program arguments flags are parsed from, replacing
+// `os.Args[1:]`. The program name should not be included.
+func WithArgs(args []string) Option {
+	return func(o *loadOptions) {
+		o.args = args
+	}
+}
+
+// WithEnviron sets the environment variables to load, replacing
+// `os.Environ()`. Variables must be in the KEY=value format returned by
+// `os.Environ()`.
+func WithEnviron(environ []string) Option {
+	return func(o *loadOptions) {
+		o.environ = environ
+	}
+}
+
+// WithoutFlags disables loading values from flags. This is useful when the
+// program parses its own flags.
+func WithoutFlags() Option {
+	return func(o *loadOptions) {
+		o.withoutFlags = true
+	}
+}
+
+// WithoutIncludes disables the include and extends directives of
+// configuration files. The include and extends keys are loaded as ordinary
+// values instead. Use this if the application has its own settings named
+// include or extends.
+func WithoutIncludes() Option {
+	return func(o *loadOptions) {
+		o.withoutIncludes = true
+	}
+}
+
+// WithoutSystemSearchPaths disables searching the user and system
+// configuration directories such as ~/.config/my-app/ and /etc/my-app/. Only
+// the search paths and their parents are searched.
+func WithoutSystemSearchPaths() Option {
+	return func(o *loadOptions) {
+		o.systemSearchPaths = false
+	}
+}
+
+// WithBoundaryMarkers stops the upward search for configuration files at the
+// first directory containing any of the given entries, for example `.git`.
+// Files in the directory containing the marker are still loaded.
+func WithBoundaryMarkers(markers ...string) Option {
+	return func(o *loadOptions) {
+		o.boundaryMarkers = markers
+	}
+}
+
+// WithHomeBoundary stops the upward search for configuration files at the
+// user's home directory.
+func WithHomeBoundary() Option {
+	return func(o *loadOptions) {
+		o.homeBoundary = true
+	}
+}
+
+// WithConfigReplacingSearch makes configuration files given with the --config
+// flag or MY_APP__CONFIG environment variable replace the files found by
+// searching, rather than being layered on top of them.
+func WithConfigReplacingSearch() Option {
+	return func(o *loadOptions) {
+		o.configReplacesSearch = true
+	}
+}
+
+// WithProfiles selects the profiles whose configuration overlays, such as
+// my-app.production.config.toml, are layered on top of the base configuration
+// files. Later profiles take precedence over earlier ones. Profiles given with
+// the --profile flag or MY_APP__PROFILE environment variable replace these.
+func WithProfiles(profiles ...string) Option {
+	return func(o *loadOptions) {
+		o.profiles = splitProfiles(strings.Join(profiles, ","))
+	}
+}
+
+// WithDotenvFileNames sets the dotenv file names to search for, replacing
+// .env. Calling it without any names disables loading dotenv files.
+func WithDotenvFileNames(dotenvFileNames ...string) Option {
+	return func(o *loadOptions) {
+		o.dotenvFileNames = dotenvFileNames
+	}
+}
+
+// WithFileReferences reads values from the files referenced by environment
+// variables ending in _FILE, such as MY_APP__DB__PASSWORD_FILE=/run/secrets/db,
+// into the path without the suffix. It is an error if a referenced file does
+// not exist. File references are off by default so settings that happen to end
+// in _file, such as a log file path, are not mistaken for them.
+func WithFileReferences() Option {
+	return func(o *loadOptions) {
+		o.fileReferences = true
+	}
+}
+
+// WithSecretDirectories loads values from directories holding one file per
+// value, such as /run/secrets. Directories given first take precedence.
+// Directories that do not exist are skipped. See NewDirectorySource for how
+// file names are mapped to paths.
+func WithSecretDirectories(secretDirs ...string) Option {
+	return func(o *loadOptions) {
+		o.secretDirs = secretDirs
+	}
+}
+
+// WithDecryptionKey sets the key used to decrypt values encrypted with
+// EncryptValue. A key given by the MY_APP__CONFIG_KEY environment variable
+// replaces it.
+func WithDecryptionKey(key string) Option {
+	return func(o *loadOptions) {
+		o.decryptionKey = key
+	}
+}
+
+// WithDecryptionKeyFile sets the path of a file holding the key used to
+// decrypt values encrypted with EncryptValue. It is an error if the file does
+// not exist. A key given by the MY_APP__CONFIG_KEY environment variable
+// replaces it.
+func WithDecryptionKeyFile(keyFilePath string) Option {
+	return func(o *loadOptions) {
+		o.decryptionKeyFilePath = keyFilePath
+	}
+}
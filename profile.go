@@ -0,0 +1,66 @@
+package orale
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// takeProfiles removes the reserved profile key from the flag and environment
+// values and returns the profiles they select. Profiles given by flags replace
+// those given by the environment. Each value may hold several comma separated
+// profiles. If the key is empty no profiles are taken.
+func takeProfiles(profileKey string, flagValues, environmentValues map[string][]any) []string {
+	if profileKey == "" {
+		return nil
+	}
+
+	flagProfiles := flagValues[profileKey]
+	environmentProfiles := environmentValues[profileKey]
+	delete(flagValues, profileKey)
+	delete(environmentValues, profileKey)
+
+	profileValues := environmentProfiles
+	if len(flagProfiles) != 0 {
+		profileValues = flagProfiles
+	}
+	profiles := []string{}
+	for _, profileValue := range profileValues {
+		if str, ok := profileValue.(string); ok {
+			profiles = append(profiles, splitProfiles(str)...)
+		}
+	}
+	return profiles
+}
+
+// splitProfiles splits a comma separated list of profiles such as
+// production,eu-west. Empty profiles and profiles containing path separators
+// are dropped.
+func splitProfiles(str string) []string {
+	profiles := []string{}
+	for _, profile := range strings.Split(str, ",") {
+		profile = strings.TrimSpace(profile)
+		if profile == "" || strings.ContainsAny(profile, `/\`) {
+			continue
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+// profileConfigNames returns the overlay file names of a profile for the
+// given configuration file names. The profile is inserted before the .config
+// part of the name, so my-app.config.toml becomes
+// my-app.production.config.toml. Names without a .config part have the profile
+// inserted before the extension, so app.toml becomes app.production.toml.
+func profileConfigNames(configNames []string, profile string) []string {
+	profileNames := []string{}
+	for _, configName := range configNames {
+		if configIndex := strings.Index(configName, ".config."); configIndex != -1 {
+			profileNames = append(profileNames, configName[:configIndex]+"."+profile+configName[configIndex:])
+			continue
+		}
+		ext := filepath.Ext(configName)
+		profileNames = append(profileNames, strings.TrimSuffix(configName, ext)+"."+profile+ext)
+	}
+	return profileNames
+}
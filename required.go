@@ -0,0 +1,70 @@
+package orale
+
+import (
+	"reflect"
+	"strings"
+)
+
+// MissingValuesError is returned by Get when fields marked as required are
+// not provided by any source. It lists every missing path at once.
+type MissingValuesError struct {
+	MissingValues []MissingValue
+}
+
+// MissingValue describes a required path that was not provided, along with
+// the flag, environment variable and configuration file key that would
+// provide it.
+type MissingValue struct {
+	Path                string
+	Flag                string
+	EnvironmentVariable string
+	FileKey             string
+}
+
+func (e *MissingValuesError) Error() string {
+	message := "missing required configuration values:"
+	for _, missingValue := range e.MissingValues {
+		message += "\n  " + missingValue.Path +
+			": set flag " + missingValue.Flag +
+			", environment variable " + missingValue.EnvironmentVariable +
+			", or key " + missingValue.FileKey + " in a configuration file"
+	}
+	return message
+}
+
+// checkRequired walks the target after it has been populated and returns a
+// MissingValuesError if any field tagged as required has no value in any
+// source. Fields with a `default` tag are always satisfied.
+func checkRequired(l *Loader, currentPath string, targetRefVal reflect.Value) error {
+	missingValues := []MissingValue{}
+	walkFields(currentPath, targetRefVal, func(fieldPath string, field reflect.StructField, _, _ reflect.Value) bool {
+		if !parseConfigTag(field).required {
+			return true
+		}
+		if _, ok := field.Tag.Lookup("default"); ok || hasPath(l, fieldPath) {
+			return true
+		}
+		missingValues = append(missingValues, newMissingValue(l, fieldPath))
+		return false
+	})
+	if len(missingValues) != 0 {
+		return &MissingValuesError{MissingValues: missingValues}
+	}
+	return nil
+}
+
+func newMissingValue(l *Loader, path string) MissingValue {
+	unescapedPath := strings.ReplaceAll(path, "\\.", ".")
+
+	flag := strings.ReplaceAll(path, ".", "--")
+	flag = strings.ReplaceAll(flag, "_", "-")
+
+	environmentVariable := strings.ToUpper(strings.ReplaceAll(path, ".", "__"))
+
+	return MissingValue{
+		Path:                unescapedPath,
+		Flag:                "--" + strings.ReplaceAll(flag, "\\--", "."),
+		EnvironmentVariable: l.envVarPrefix + "__" + strings.ReplaceAll(environmentVariable, "\\__", "."),
+		FileKey:             unescapedPath,
+	}
+}
@@ -0,0 +1,91 @@
+package orale_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	orale "github.com/RobertWHurst/orale"
+)
+
+func TestGetRequired(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should collect every missing required path into one error", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Database struct {
+				ConnectionUri string `config:"connection_uri,required"`
+				PoolSize      int    `config:"pool_size,required" default:"4"`
+			} `config:"db"`
+			ServerPort int    `config:"server_port,required"`
+			Name       string `config:"name,required"`
+		}
+
+		conf, err := orale.LoadFromValues([]string{"--name=orale"}, "MY_APP", []string{}, "", []string{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = conf.Get("", &TestStruct{})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		var missingValuesErr *orale.MissingValuesError
+		if !errors.As(err, &missingValuesErr) {
+			t.Fatalf("expected a MissingValuesError, got %T", err)
+		}
+		if len(missingValuesErr.MissingValues) != 2 {
+			t.Fatalf("expected 2 missing values, got %d", len(missingValuesErr.MissingValues))
+		}
+
+		missingValue := missingValuesErr.MissingValues[0]
+		if missingValue.Path != "db.connection_uri" {
+			t.Fatalf("expected path to be db.connection_uri, got %s", missingValue.Path)
+		}
+		if missingValue.Flag != "--db--connection-uri" {
+			t.Fatalf("expected flag to be --db--connection-uri, got %s", missingValue.Flag)
+		}
+		if missingValue.EnvironmentVariable != "MY_APP__DB__CONNECTION_URI" {
+			t.Fatalf("expected environment variable to be MY_APP__DB__CONNECTION_URI, got %s", missingValue.EnvironmentVariable)
+		}
+		if missingValue.FileKey != "db.connection_uri" {
+			t.Fatalf("expected file key to be db.connection_uri, got %s", missingValue.FileKey)
+		}
+		if missingValuesErr.MissingValues[1].Path != "server_port" {
+			t.Fatalf("expected second path to be server_port, got %s", missingValuesErr.MissingValues[1].Path)
+		}
+		if !strings.Contains(err.Error(), "MY_APP__SERVER_PORT") {
+			t.Fatalf("expected error message to name the environment variable, got %s", err)
+		}
+	})
+
+	t.Run("should accept required values from any source", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Database struct {
+				ConnectionUri string `config:"connection_uri,required"`
+			} `config:"db,required"`
+		}
+
+		conf := &orale.Loader{
+			ConfigurationFiles: []*orale.File{
+				{
+					Path:   "path/to/file.toml",
+					Values: map[string][]any{"db.connection_uri": {"protocol://"}},
+				},
+			},
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+		if testStruct.Database.ConnectionUri != "protocol://" {
+			t.Fatalf("expected Database.ConnectionUri to be protocol://, got %s", testStruct.Database.ConnectionUri)
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package orale
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+)
+
+// redactedText replaces the value of a Secret wherever it would be printed.
+const redactedText = "[REDACTED]"
+
+// Secret holds a configuration value that must not be printed or logged, such
+// as a password or API key. Get decodes into the wrapped type as if the field
+// were not wrapped, and validation rules apply to the wrapped value. Printing,
+// logging or marshaling the secret produces [REDACTED]; the value is only
+// available from Reveal.
+//
+//	type Config struct {
+//		Database struct {
+//			Password orale.Secret[string] `config:"password" validate:"min=12"`
+//		} `config:"database"`
+//	}
+//
+//	db.Connect(config.Database.Password.Reveal())
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret wraps a value in a Secret.
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{value: value}
+}
+
+// Reveal returns the secret's value.
+func (s Secret[T]) Reveal() T {
+	return s.value
+}
+
+// String returns [REDACTED] so the value is hidden from fmt.
+func (s Secret[T]) String() string {
+	return redactedText
+}
+
+// GoString returns [REDACTED] so the value is hidden from the %#v verb.
+func (s Secret[T]) GoString() string {
+	return redactedText
+}
+
+// MarshalJSON encodes the secret as the string [REDACTED].
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactedText)
+}
+
+// MarshalText encodes the secret as [REDACTED] for encoders such as TOML and
+// YAML.
+func (s Secret[T]) MarshalText() ([]byte, error) {
+	return []byte(redactedText), nil
+}
+
+// LogValue logs the secret as [REDACTED] with log/slog.
+func (s Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(redactedText)
+}
+
+func (s *Secret[T]) secretRefVal() reflect.Value {
+	return reflect.ValueOf(&s.value).Elem()
+}
+
+// secretHolder is implemented by pointers to Secret of any type. It gives
+// access to the wrapped value so it can be decoded, validated and walked.
+type secretHolder interface {
+	secretRefVal() reflect.Value
+}
+
+var secretHolderType = reflect.TypeOf((*secretHolder)(nil)).Elem()
+
+// isSecretType returns true if the type is a Secret of any type.
+func isSecretType(refType reflect.Type) bool {
+	return refType.Kind() == reflect.Struct && reflect.PointerTo(refType).Implements(secretHolderType)
+}
+
+// secretInnerValue returns the value wrapped by a Secret. The boolean result
+// is false if the value is not an addressable Secret.
+func secretInnerValue(refVal reflect.Value) (reflect.Value, bool) {
+	if !refVal.CanAddr() || !isSecretType(refVal.Type()) {
+		return reflect.Value{}, false
+	}
+	return refVal.Addr().Interface().(secretHolder).secretRefVal(), true
+}
+
+// secretInnerType returns the type wrapped by a Secret type.
+func secretInnerType(refType reflect.Type) reflect.Type {
+	return reflect.New(refType).Interface().(secretHolder).secretRefVal().Type()
+}
+
+// maybeDecodeSecret decodes the value at the current path into the value
+// wrapped by the target if the target is a Secret. Conversion errors do not
+// include the value. The boolean result is true if the target is a Secret.
+func maybeDecodeSecret(l *Loader, currentPath string, targetRefVal reflect.Value, index int) (bool, error) {
+	innerRefVal, ok := secretInnerValue(targetRefVal)
+	if !ok {
+		return false, nil
+	}
+	if err := getFromLoader(l, currentPath, innerRefVal, index); err != nil {
+		_, source, _ := resolveValue(l, currentPath)
+		if source == "" {
+			return true, fmt.Errorf("cannot decode secret value at path %s to %s", currentPath, targetRefVal.Type())
+		}
+		return true, fmt.Errorf("cannot decode secret value at path %s from %s to %s", currentPath, source, targetRefVal.Type())
+	}
+	return true, nil
+}
+
+// WithSecretsOf returns a copy of the loader that also treats the paths of
+// the Secret fields of cfg as secrets, so Explain and Dump redact them. cfg is
+// the configuration struct, or a pointer to it, that is populated from the
+// root path by Get. The loader itself is not changed.
+//
+//	fmt.Println(loader.WithSecretsOf(Config{}).Explain("database.password"))
+func (l *Loader) WithSecretsOf(cfg any) *Loader {
+	secretsLoader := *l
+	secretsLoader.secretTypes = append(append([]reflect.Type{}, l.secretTypes...), reflect.TypeOf(cfg))
+	return &secretsLoader
+}
+
+// markSensitive records that the values at and beneath the paths must be
+// redacted by Explain and Dump. It is called by Load for values that were
+// decrypted or read from secret files, before the loader is returned.
+func (l *Loader) markSensitive(paths ...string) {
+	if l.sensitivePaths == nil {
+		l.sensitivePaths = map[string]bool{}
+	}
+	for _, path := range paths {
+		l.sensitivePaths[path] = true
+	}
+}
+
+// isSensitive returns true if the path, or a path containing it, has been
+// marked as sensitive or leads to a Secret field of a type given to
+// WithSecretsOf.
+func (l *Loader) isSensitive(path string) bool {
+	for sensitivePath := range l.sensitivePaths {
+		if path == sensitivePath || isSubPath(path, sensitivePath) {
+			return true
+		}
+	}
+	if len(l.secretTypes) == 0 {
+		return false
+	}
+	segments := splitPath(path)
+	for _, secretType := range l.secretTypes {
+		if isSecretPath(secretType, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSecretPath returns true if the path segments lead to, or into, a Secret
+// within the type. Fields are matched by their config tags as they are by Get.
+// Slices of secrets are secret as a whole, as their entries may be given as a
+// single multi value.
+func isSecretPath(refType reflect.Type, segments []pathSegment) bool {
+	for {
+		for refType != nil && refType.Kind() == reflect.Ptr {
+			refType = refType.Elem()
+		}
+		if refType == nil {
+			return false
+		}
+		if isSecretType(refType) {
+			return true
+		}
+		if len(segments) == 0 {
+			if refType.Kind() != reflect.Slice && refType.Kind() != reflect.Array {
+				return false
+			}
+			elemType := refType.Elem()
+			for elemType.Kind() == reflect.Ptr {
+				elemType = elemType.Elem()
+			}
+			return isSecretType(elemType)
+		}
+
+		segment := segments[0]
+		segments = segments[1:]
+		switch refType.Kind() {
+		case reflect.Struct:
+			if segment.isIndex {
+				return false
+			}
+			var fieldType reflect.Type
+			for i := 0; i < refType.NumField(); i += 1 {
+				field := refType.Field(i)
+				if field.IsExported() && parseConfigTag(field).name == segment.key {
+					fieldType = field.Type
+					break
+				}
+			}
+			refType = fieldType
+		case reflect.Slice, reflect.Array:
+			if !segment.isIndex {
+				return false
+			}
+			refType = refType.Elem()
+		case reflect.Map:
+			if segment.isIndex {
+				return false
+			}
+			refType = refType.Elem()
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package orale_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	orale "github.com/RobertWHurst/orale"
+)
+
+func TestSecret(t *testing.T) {
+	t.Parallel()
+
+	type TestConfig struct {
+		Database struct {
+			User     string               `config:"user"`
+			Password orale.Secret[string] `config:"password" validate:"min=8"`
+		} `config:"database"`
+		Pin    orale.Secret[int]      `config:"pin"`
+		Tokens orale.Secret[[]string] `config:"tokens" default:"a,b"`
+	}
+
+	t.Run("should decode into the wrapped value and redact it when printed", func(t *testing.T) {
+		t.Parallel()
+
+		conf := &orale.Loader{
+			FlagValues:        map[string][]any{},
+			EnvironmentValues: map[string][]any{"database.password": {"hunter2-hunter2"}, "pin": {"1234"}},
+			ConfigurationFiles: []*orale.File{
+				{Values: map[string][]any{"database.user": {"admin"}}},
+			},
+		}
+		testConf := TestConfig{}
+		if err := conf.Get("", &testConf); err != nil {
+			t.Fatal(err)
+		}
+		if testConf.Database.Password.Reveal() != "hunter2-hunter2" {
+			t.Fatalf("expected the password to be hunter2-hunter2, got %s", testConf.Database.Password.Reveal())
+		}
+		if testConf.Pin.Reveal() != 1234 {
+			t.Fatalf("expected the pin to be 1234, got %d", testConf.Pin.Reveal())
+		}
+		if tokens := testConf.Tokens.Reveal(); len(tokens) != 2 || tokens[0] != "a" || tokens[1] != "b" {
+			t.Fatalf("expected the tokens to be [a b], got %v", tokens)
+		}
+
+		jsonBytes, err := json.Marshal(testConf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		logBuffer := &bytes.Buffer{}
+		slog.New(slog.NewTextHandler(logBuffer, nil)).Info("loaded", "password", testConf.Database.Password)
+
+		for _, output := range []string{
+			fmt.Sprintf("%v", testConf),
+			fmt.Sprintf("%+v", testConf),
+			fmt.Sprintf("%#v", testConf),
+			fmt.Sprint(testConf.Database.Password),
+			string(jsonBytes),
+			logBuffer.String(),
+		} {
+			if strings.Contains(output, "hunter2") || strings.Contains(output, "1234") {
+				t.Fatalf("expected the secrets to be redacted, got %s", output)
+			}
+			if !strings.Contains(output, "[REDACTED]") {
+				t.Fatalf("expected [REDACTED] in the output, got %s", output)
+			}
+		}
+	})
+
+	t.Run("should validate the wrapped value and keep it out of errors", func(t *testing.T) {
+		t.Parallel()
+
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{"database.password": {"short"}},
+		}
+		err := conf.Get("", &TestConfig{})
+		if _, ok := err.(*orale.ValidationError); !ok {
+			t.Fatalf("expected a validation error, got %v", err)
+		}
+		if strings.Contains(err.Error(), "short") {
+			t.Fatalf("expected the secret to be kept out of the error, got %s", err)
+		}
+
+		conf = &orale.Loader{
+			EnvironmentValues: map[string][]any{"pin": {"not-a-number"}},
+		}
+		err = conf.Get("", &TestConfig{})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if strings.Contains(err.Error(), "not-a-number") {
+			t.Fatalf("expected the secret to be kept out of the error, got %s", err)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package orale
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fileReferenceSuffix marks a path whose value is the path of a file holding
+// the real value, for example MY_APP__DB__PASSWORD_FILE=/run/secrets/db.
+const fileReferenceSuffix = "_file"
+
+// resolveFileReferences reads the files referenced by paths ending in _file,
+// such as db.password_file, into the path without the suffix. The path without
+// the suffix is only set if it has no value of its own, and the reference
+// itself is left in place. Relative file paths are resolved against baseDir.
+// The paths set are returned, mapped to the paths referencing them.
+func resolveFileReferences(values map[string][]any, baseDir string) (map[string]string, error) {
+	referencePaths := []string{}
+	for path := range values {
+		if strings.HasSuffix(path, fileReferenceSuffix) && len(path) > len(fileReferenceSuffix) {
+			referencePaths = append(referencePaths, path)
+		}
+	}
+	sort.Strings(referencePaths)
+
+	resolvedPaths := map[string]string{}
+	for _, referencePath := range referencePaths {
+		targetPath := strings.TrimSuffix(referencePath, fileReferenceSuffix)
+		if _, ok := values[targetPath]; ok {
+			continue
+		}
+
+		targetValues := []any{}
+		for _, referenceValue := range values[referencePath] {
+			filePath, ok := referenceValue.(string)
+			if !ok || filePath == "" {
+				continue
+			}
+			if baseDir != "" && !filepath.IsAbs(filePath) {
+				filePath = filepath.Join(baseDir, filePath)
+			}
+			contents, err := os.ReadFile(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the file referenced by %s: %w", referencePath, err)
+			}
+			targetValues = append(targetValues, trimTrailingNewline(string(contents)))
+		}
+		if len(targetValues) != 0 {
+			values[targetPath] = targetValues
+			resolvedPaths[targetPath] = referencePath
+		}
+	}
+
+	return resolvedPaths, nil
+}
+
+// NewDirectorySource creates a Source from a directory holding one file per
+// value, such as the secrets mounted by Docker and Kubernetes or the
+// credentials passed to a service by systemd in $CREDENTIALS_DIRECTORY. Each
+// file name is mapped to a path, with periods and double underscores
+// separating keys, so db.password and db__password both become db.password.
+// Subdirectories add a key to the path of the files within them. Hidden files
+// and directories are skipped. A single trailing newline is removed from each
+// value.
+func NewDirectorySource(dirPath string) (Source, error) {
+	values := map[string][]any{}
+	locations := map[string][]string{}
+	if err := loadDirectoryValues(dirPath, "", values, locations); err != nil {
+		return nil, err
+	}
+	return newLocatedSource(dirPath, values, locations), nil
+}
+
+func loadDirectoryValues(dirPath, currentPath string, values map[string][]any, locations map[string][]string) error {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		entryPath := filepath.Join(dirPath, entry.Name())
+		// Stat rather than using the entry so symlinks, which Kubernetes uses
+		// for each key of a mounted volume, are followed.
+		info, err := os.Stat(entryPath)
+		if err != nil {
+			return err
+		}
+
+		path := joinPath(currentPath, strings.ReplaceAll(strings.ToLower(entry.Name()), "__", "."))
+		if info.IsDir() {
+			if err := loadDirectoryValues(entryPath, path, values, locations); err != nil {
+				return err
+			}
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		contents, err := os.ReadFile(entryPath)
+		if err != nil {
+			return err
+		}
+		values[path] = []any{trimTrailingNewline(string(contents))}
+		locations[path] = []string{entryPath}
+	}
+
+	return nil
+}
+
+// loadDirectorySources loads a directory source for each of the secret
+// directories. Directories that do not exist are skipped.
+func loadDirectorySources(options *loadOptions) ([]Source, error) {
+	secretDirs := append([]string{}, options.secretDirs...)
+	if options.credentialsDirectory {
+		if credentialsDir := options.getenv("CREDENTIALS_DIRECTORY"); credentialsDir != "" {
+			secretDirs = append(secretDirs, credentialsDir)
+		}
+	}
+
+	directorySources := []Source{}
+	for _, secretDir := range secretDirs {
+		directorySource, err := NewDirectorySource(secretDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to load secret directory %s: %w", secretDir, err)
+		}
+		directorySources = append(directorySources, directorySource)
+	}
+	return directorySources, nil
+}
+
+func trimTrailingNewline(str string) string {
+	str = strings.TrimSuffix(str, "\n")
+	return strings.TrimSuffix(str, "\r")
+}
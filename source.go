@@ -0,0 +1,73 @@
+package orale
+
+// Source provides configuration values to a Loader. Values are flattened into
+// paths in the same format as File.Values, with nested keys separated by
+// periods and slice indexes in square brackets, for example
+// `database.replicas[0].host`.
+//
+// Custom sources such as a database table, a vendor API or test fixtures can
+// be added to a loader by setting Loader.Sources, for example to the loader's
+// BuiltinSources with the custom source inserted at the desired precedence.
+// Sources may also implement Locator to describe where each value was found.
+type Source interface {
+	// Name identifies the source in errors and explanations.
+	Name() string
+	// FlatValues returns the source's values by path. It is called each time a
+	// path is resolved, so sources should load their values ahead of time.
+	FlatValues() map[string][]any
+}
+
+// NewSource creates a Source from a name and a map of values by path.
+func NewSource(name string, values map[string][]any) Source {
+	return &valuesSource{name: name, values: values}
+}
+
+type valuesSource struct {
+	name   string
+	values map[string][]any
+}
+
+func (s *valuesSource) Name() string {
+	return s.name
+}
+
+func (s *valuesSource) FlatValues() map[string][]any {
+	return s.values
+}
+
+// Names of the built in sources.
+const (
+	flagSourceName        = "flags"
+	environmentSourceName = "environment"
+	defaultSourceName     = "default"
+)
+
+// sources returns the loader's sources ordered from highest to lowest
+// precedence. If Sources is not set the sources are taken from BuiltinSources.
+func (l *Loader) sources() []Source {
+	if l.Sources != nil {
+		return l.Sources
+	}
+	return l.BuiltinSources()
+}
+
+// BuiltinSources returns the sources built from the loader's fields, ordered
+// from highest to lowest precedence: FlagValues, EnvironmentValues, the secret
+// directories given to Load, DotenvFiles and ConfigurationFiles. Values are
+// resolved from these sources while Sources is nil. To add a custom source to
+// a loaded configuration, set Sources to these sources with the custom source
+// inserted at the desired precedence.
+func (l *Loader) BuiltinSources() []Source {
+	sources := []Source{
+		newLocatedSource(flagSourceName, l.FlagValues, l.flagLocations),
+		newLocatedSource(environmentSourceName, l.EnvironmentValues, l.environmentLocations),
+	}
+	sources = append(sources, l.directorySources...)
+	for _, file := range l.DotenvFiles {
+		sources = append(sources, file)
+	}
+	for _, file := range l.ConfigurationFiles {
+		sources = append(sources, file)
+	}
+	return sources
+}
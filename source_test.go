@@ -0,0 +1,149 @@
+package orale_test
+
+import (
+	"strings"
+	"testing"
+
+	orale "github.com/RobertWHurst/orale"
+)
+
+type testTableSource struct {
+	rows map[string]string
+}
+
+func (s *testTableSource) Name() string {
+	return "settings table"
+}
+
+func (s *testTableSource) FlatValues() map[string][]any {
+	values := map[string][]any{}
+	for key, value := range s.rows {
+		values[key] = []any{value}
+	}
+	return values
+}
+
+func TestSources(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should resolve values over an ordered list of sources", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			A string `config:"a"`
+			B string `config:"b"`
+			C string `config:"c"`
+		}
+
+		conf := &orale.Loader{
+			Sources: []orale.Source{
+				orale.NewSource("fixtures", map[string][]any{"a": {"1"}}),
+				&testTableSource{rows: map[string]string{"a": "2", "b": "3"}},
+				&orale.File{Path: "path/to/file.toml", Values: map[string][]any{"b": {"4"}, "c": {"5"}}},
+			},
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if testStruct.A != "1" {
+			t.Fatalf("expected A to be 1, got %s", testStruct.A)
+		}
+		if testStruct.B != "3" {
+			t.Fatalf("expected B to be 3, got %s", testStruct.B)
+		}
+		if testStruct.C != "5" {
+			t.Fatalf("expected C to be 5, got %s", testStruct.C)
+		}
+	})
+
+	t.Run("should allow custom sources to be added to a loaded config", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Port int    `config:"port"`
+			Name string `config:"name"`
+		}
+
+		conf, err := orale.LoadFromValues([]string{"--port=8080"}, "TEST", []string{"TEST__NAME=env"}, "", []string{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sources := conf.BuiltinSources()
+		if len(sources) != 2 {
+			t.Fatalf("expected 2 sources, got %d", len(sources))
+		}
+		if sources[0].Name() != "flags" || sources[1].Name() != "environment" {
+			t.Fatalf("expected flags then environment sources, got %s and %s", sources[0].Name(), sources[1].Name())
+		}
+
+		table := &testTableSource{rows: map[string]string{"port": "9000", "name": "table"}}
+		conf.Sources = append([]orale.Source{sources[0], table}, sources[1:]...)
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if testStruct.Port != 8080 {
+			t.Fatalf("expected Port to be 8080, got %d", testStruct.Port)
+		}
+		if testStruct.Name != "table" {
+			t.Fatalf("expected Name to be table, got %s", testStruct.Name)
+		}
+	})
+
+	t.Run("should resolve files added to a loaded config", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Port int    `config:"port"`
+			Name string `config:"name"`
+		}
+
+		conf, err := orale.LoadFromValues([]string{}, "TEST", []string{"TEST__NAME=env"}, "", []string{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		conf.ConfigurationFiles = append(conf.ConfigurationFiles, &orale.File{
+			Path:   "path/to/file.toml",
+			Values: map[string][]any{"port": {int64(8080)}, "name": {"file"}},
+		})
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if testStruct.Port != 8080 {
+			t.Fatalf("expected Port to be 8080, got %d", testStruct.Port)
+		}
+		if testStruct.Name != "env" {
+			t.Fatalf("expected Name to be env, got %s", testStruct.Name)
+		}
+	})
+
+	t.Run("should name custom sources in errors", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Port int `config:"port"`
+		}
+
+		conf := &orale.Loader{
+			Sources: []orale.Source{
+				&testTableSource{rows: map[string]string{"port": "eighty"}},
+			},
+		}
+
+		err := conf.Get("", &TestStruct{})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !strings.Contains(err.Error(), "settings table") {
+			t.Fatalf("expected error to name the source, got %s", err)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package orale
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// resolveSubtree rebuilds the nested value beneath the target path with the
+// same precedence as Get. The highest precedence source providing a value at
+// or beneath a path decides whether the path holds a value, a slice or a
+// table, so a value overrides the tables and slices of lower precedence
+// sources and the reverse. Slices take their length from the highest
+// precedence source providing entries as resolvePathLen does, while tables
+// contain the keys found in every source as struct and map fields do. Tables
+// become map[string]any and slices become []any. If nothing is found at or
+// beneath the path nil is returned. If redact is true, sensitive values are
+// replaced with [REDACTED] as they are by Explain.
+func resolveSubtree(l *Loader, targetPath string, redact bool) (any, error) {
+	source := findPathSource(l, targetPath)
+	if source == nil {
+		return nil, nil
+	}
+
+	if targetPath != "" {
+		if redact && l.isSensitive(targetPath) {
+			return redactedText, nil
+		}
+
+		if value, ok := source.FlatValues()[targetPath]; ok {
+			if len(value) == 1 {
+				return value[0], nil
+			}
+			return value, nil
+		}
+
+		if valueLen := sourcePathLen(source, targetPath); valueLen != 0 {
+			entries := make([]any, valueLen)
+			for i := 0; i < valueLen; i += 1 {
+				entry, err := resolveSubtree(l, fmt.Sprintf("%s[%d]", targetPath, i), redact)
+				if err != nil {
+					return nil, err
+				}
+				entries[i] = entry
+			}
+			return entries, nil
+		}
+	}
+
+	keys, err := resolvePathKeys(l, targetPath)
+	if err != nil {
+		return nil, err
+	}
+	table := map[string]any{}
+	for _, key := range keys {
+		value, err := resolveSubtree(l, joinPath(targetPath, key), redact)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			table[unescapePathKey(key)] = value
+		}
+	}
+	return table, nil
+}
+
+// isSubPath returns true if the subject path is nested beneath the target
+// path. An empty target path contains every path.
+func isSubPath(subjectPath, targetPath string) bool {
+	if targetPath == "" {
+		return subjectPath != ""
+	}
+	if len(subjectPath) <= len(targetPath) || subjectPath[:len(targetPath)] != targetPath {
+		return false
+	}
+	return subjectPath[len(targetPath)] == '.' || subjectPath[len(targetPath)] == '['
+}
+
+// escapePathKey escapes the periods within a key so it is kept as a single
+// key when joined into a path. It reverses unescapePathKey.
+func escapePathKey(key string) string {
+	return strings.ReplaceAll(key, ".", "\\.")
+}
+
+// unescapePathKey removes the backslashes escaping periods within a key, so
+// the key a\.b becomes a.b.
+func unescapePathKey(key string) string {
+	if !strings.Contains(key, "\\") {
+		return key
+	}
+	unescaped := strings.Builder{}
+	for i := 0; i < len(key); i += 1 {
+		if key[i] == '\\' && i+1 < len(key) {
+			i += 1
+		}
+		unescaped.WriteByte(key[i])
+	}
+	return unescaped.String()
+}
+
+// pathSegment is a single step in a path. It is either a key or an index.
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// splitPath splits a path such as a.b[0].c into its segments. Escaped periods
+// are unescaped and kept as part of the key.
+func splitPath(path string) []pathSegment {
+	segments := []pathSegment{}
+	key := strings.Builder{}
+	hasKey := false
+	flushKey := func() {
+		if hasKey {
+			segments = append(segments, pathSegment{key: key.String()})
+			key.Reset()
+			hasKey = false
+		}
+	}
+
+	for i := 0; i < len(path); i += 1 {
+		switch path[i] {
+		case '\\':
+			if i+1 < len(path) {
+				i += 1
+			}
+			key.WriteByte(path[i])
+			hasKey = true
+		case '.':
+			flushKey()
+		case '[':
+			flushKey()
+			endIndex := strings.IndexByte(path[i:], ']')
+			if endIndex == -1 {
+				key.WriteString(path[i:])
+				hasKey = true
+				i = len(path)
+				continue
+			}
+			index, err := strconv.Atoi(path[i+1 : i+endIndex])
+			if err != nil {
+				key.WriteString(path[i : i+endIndex+1])
+				hasKey = true
+			} else {
+				segments = append(segments, pathSegment{index: index, isIndex: true})
+			}
+			i += endIndex
+		default:
+			key.WriteByte(path[i])
+			hasKey = true
+		}
+	}
+	flushKey()
+
+	return segments
+}
@@ -0,0 +1,65 @@
+package orale
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// loadSystemConfigurationFiles loads configuration files from the user and
+// system configuration directories. Files are returned in order of
+// precedence: $XDG_CONFIG_HOME/my-app/, ~/.config/my-app/, ~/.my-app.config.*
+// then /etc/my-app/.
+func loadSystemConfigurationFiles(options *loadOptions) ([]*File, error) {
+	if options.applicationName == "" {
+		return []*File{}, nil
+	}
+
+	// Within an application's own directory the shorter config.* names are
+	// accepted alongside the regular configuration file names.
+	appDirConfigNames := append([]string{}, options.configFileNames...)
+	for _, configName := range options.configFileNames {
+		if strings.HasPrefix(configName, options.applicationName+".config.") {
+			appDirConfigNames = append(appDirConfigNames, strings.TrimPrefix(configName, options.applicationName+"."))
+		}
+	}
+
+	homeDir := options.getenv("HOME")
+	if homeDir == "" && runtime.GOOS == "windows" {
+		homeDir = options.getenv("USERPROFILE")
+	}
+	configHomeDir := options.getenv("XDG_CONFIG_HOME")
+
+	type searchDir struct {
+		path        string
+		configNames []string
+	}
+	searchDirs := []searchDir{}
+	if configHomeDir != "" {
+		searchDirs = append(searchDirs, searchDir{filepath.Join(configHomeDir, options.applicationName), appDirConfigNames})
+	}
+	if homeDir != "" {
+		homeConfigNames := []string{}
+		for _, configName := range options.configFileNames {
+			homeConfigNames = append(homeConfigNames, "."+configName)
+		}
+		searchDirs = append(searchDirs,
+			searchDir{filepath.Join(homeDir, ".config", options.applicationName), appDirConfigNames},
+			searchDir{homeDir, homeConfigNames},
+		)
+	}
+	if runtime.GOOS != "windows" {
+		searchDirs = append(searchDirs, searchDir{filepath.Join("/etc", options.applicationName), appDirConfigNames})
+	}
+
+	configFiles := []*File{}
+	for _, dir := range searchDirs {
+		dirConfigFiles, err := loadConfigurationFilesFromDir(dir.path, dir.configNames, options.profiles, !options.withoutIncludes)
+		if err != nil {
+			return nil, err
+		}
+		configFiles = append(configFiles, dirConfigFiles...)
+	}
+
+	return configFiles, nil
+}
@@ -0,0 +1,39 @@
+package orale
+
+import (
+	"reflect"
+	"strings"
+)
+
+// configTag is the parsed form of a field's `config` tag. The tag holds the
+// field's path segment followed by comma separated options, for example
+// `config:"connection_uri,required"`.
+type configTag struct {
+	name     string
+	required bool
+}
+
+func parseConfigTag(field reflect.StructField) configTag {
+	tagChunks := strings.Split(field.Tag.Get("config"), ",")
+
+	tag := configTag{name: strings.TrimSpace(tagChunks[0])}
+	if tag.name == "" {
+		tag.name = calDefaultFieldTag(field.Name)
+	}
+	for _, option := range tagChunks[1:] {
+		switch strings.TrimSpace(option) {
+		case "required":
+			tag.required = true
+		}
+	}
+
+	return tag
+}
+
+// joinPath appends a field's path segment to the current path.
+func joinPath(currentPath, name string) string {
+	if currentPath == "" {
+		return name
+	}
+	return currentPath + "." + name
+}
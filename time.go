@@ -0,0 +1,112 @@
+package orale
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Location names given by the toml decoder to local date and time values.
+const (
+	tomlLocalDatetimeZone = "datetime-local"
+	tomlLocalDateZone     = "date-local"
+	tomlLocalTimeZone     = "time-local"
+)
+
+// timeLayouts are tried in order when parsing a string into a time.Time. The
+// layouts without an offset are interpreted in the local time zone.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"15:04:05.999999999",
+	"15:04",
+}
+
+// maybeDecodeTime decodes the value at the current path into the target if
+// the target is a time.Time or time.Duration. The boolean result is true if
+// the target is one of these types.
+func maybeDecodeTime(l *Loader, currentPath string, targetRefVal reflect.Value, index int) (bool, error) {
+	targetType := targetRefVal.Type()
+	if targetType != timeType && targetType != durationType {
+		return false, nil
+	}
+
+	value, source, err := resolveTargetValue(l, currentPath, targetType, index)
+	if err != nil || len(value) == 0 {
+		return true, err
+	}
+
+	if targetType == durationType {
+		duration, err := convertToDuration(value[0])
+		if err != nil {
+			return true, newConversionError(currentPath, source, targetType, value[0], err)
+		}
+		targetRefVal.SetInt(int64(duration))
+		return true, nil
+	}
+
+	timeValue, err := convertToTime(value[0])
+	if err != nil {
+		return true, newConversionError(currentPath, source, targetType, value[0], err)
+	}
+	targetRefVal.Set(reflect.ValueOf(timeValue))
+	return true, nil
+}
+
+// convertToDuration converts a loaded value into a time.Duration. Strings are
+// parsed with time.ParseDuration and TOML local times such as 01:30:00 become
+// the duration since midnight. Integers are rejected rather than guessing
+// their unit, so timeout = 30 does not quietly become 30ns.
+func convertToDuration(value any) (time.Duration, error) {
+	switch val := value.(type) {
+	case string:
+		return time.ParseDuration(strings.TrimSpace(val))
+	case int64:
+		return 0, fmt.Errorf("%d has no unit, use a duration string such as \"%ds\"", val, val)
+	case time.Time:
+		if val.Location().String() != tomlLocalTimeZone {
+			return 0, fmt.Errorf("only local times can be used as a duration")
+		}
+		hour, min, sec := val.Clock()
+		return time.Duration(hour)*time.Hour +
+			time.Duration(min)*time.Minute +
+			time.Duration(sec)*time.Second +
+			time.Duration(val.Nanosecond()), nil
+	}
+	return 0, newUnsupportedValueTypeError(value)
+}
+
+// convertToTime converts a loaded value into a time.Time. Strings are parsed
+// as RFC 3339 first, then as local date times, dates and times. TOML offset
+// date times are used as is while TOML local date times, local dates and
+// local times are interpreted in the local time zone. Local times are placed
+// on the zero date.
+func convertToTime(value any) (time.Time, error) {
+	switch val := value.(type) {
+	case string:
+		str := strings.TrimSpace(val)
+		for _, layout := range timeLayouts {
+			if timeValue, err := time.ParseInLocation(layout, str, time.Local); err == nil {
+				return timeValue, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("%q is not an RFC 3339 date time, date or time", str)
+	case time.Time:
+		switch val.Location().String() {
+		case tomlLocalDatetimeZone, tomlLocalDateZone, tomlLocalTimeZone:
+			return time.Date(val.Year(), val.Month(), val.Day(), val.Hour(), val.Minute(), val.Second(), val.Nanosecond(), time.Local), nil
+		}
+		return val, nil
+	}
+	return time.Time{}, newUnsupportedValueTypeError(value)
+}
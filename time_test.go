@@ -0,0 +1,141 @@
+package orale_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	orale "github.com/RobertWHurst/orale"
+)
+
+func TestGetTime(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should decode durations and times from strings", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Timeout   time.Duration   `config:"timeout"`
+			Intervals []time.Duration `config:"intervals"`
+			StartedAt time.Time       `config:"started_at"`
+			Deadline  *time.Time      `config:"deadline"`
+		}
+
+		conf := &orale.Loader{
+			FlagValues: map[string][]any{
+				"intervals": {"1s", "2m"},
+			},
+			EnvironmentValues: map[string][]any{
+				"timeout":    {"1h30m"},
+				"started_at": {"2023-06-01T12:30:00Z"},
+				"deadline":   {"2023-06-02"},
+			},
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if testStruct.Timeout != 90*time.Minute {
+			t.Fatalf("expected Timeout to be 1h30m, got %s", testStruct.Timeout)
+		}
+		if len(testStruct.Intervals) != 2 || testStruct.Intervals[1] != 2*time.Minute {
+			t.Fatalf("expected Intervals to be [1s 2m], got %v", testStruct.Intervals)
+		}
+		if !testStruct.StartedAt.Equal(time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC)) {
+			t.Fatalf("expected StartedAt to be 2023-06-01T12:30:00Z, got %s", testStruct.StartedAt)
+		}
+		if testStruct.Deadline == nil || !testStruct.Deadline.Equal(time.Date(2023, 6, 2, 0, 0, 0, 0, time.Local)) {
+			t.Fatalf("expected Deadline to be 2023-06-02 local, got %s", testStruct.Deadline)
+		}
+	})
+
+	t.Run("should decode TOML date time values", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Timeout        time.Duration `config:"timeout"`
+			StartedAt      time.Time     `config:"started_at"`
+			LocalStartedAt time.Time     `config:"local_started_at"`
+			Birthday       time.Time     `config:"birthday"`
+			Alarm          time.Time     `config:"alarm"`
+			AlarmOffset    time.Duration `config:"alarm_offset"`
+		}
+
+		conf, err := orale.LoadFromValues([]string{}, "", []string{}, testAssetsPath, []string{"test-time.toml"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if testStruct.Timeout != 90*time.Minute {
+			t.Fatalf("expected Timeout to be 1h30m, got %s", testStruct.Timeout)
+		}
+		if !testStruct.StartedAt.Equal(time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC)) {
+			t.Fatalf("expected StartedAt to be 2023-06-01T12:30:00Z, got %s", testStruct.StartedAt)
+		}
+		if !testStruct.LocalStartedAt.Equal(time.Date(2023, 6, 1, 12, 30, 0, 0, time.Local)) {
+			t.Fatalf("expected LocalStartedAt to be 2023-06-01T12:30:00 local, got %s", testStruct.LocalStartedAt)
+		}
+		if testStruct.LocalStartedAt.Location() != time.Local {
+			t.Fatalf("expected LocalStartedAt to be in the local time zone, got %s", testStruct.LocalStartedAt.Location())
+		}
+		if !testStruct.Birthday.Equal(time.Date(2023, 6, 1, 0, 0, 0, 0, time.Local)) {
+			t.Fatalf("expected Birthday to be 2023-06-01 local, got %s", testStruct.Birthday)
+		}
+		if hour, min, _ := testStruct.Alarm.Clock(); hour != 7 || min != 30 {
+			t.Fatalf("expected Alarm to be 07:30, got %s", testStruct.Alarm)
+		}
+		if testStruct.AlarmOffset != 7*time.Hour+30*time.Minute {
+			t.Fatalf("expected AlarmOffset to be 7h30m, got %s", testStruct.AlarmOffset)
+		}
+	})
+
+	t.Run("should return an error for invalid durations", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Timeout time.Duration `config:"timeout"`
+		}
+
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{
+				"timeout": {"30"},
+			},
+		}
+
+		if err := conf.Get("", &TestStruct{}); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("should return an error for integer durations", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Timeout time.Duration `config:"timeout"`
+		}
+
+		conf := &orale.Loader{
+			ConfigurationFiles: []*orale.File{
+				{
+					Path:   "path/to/file.toml",
+					Values: map[string][]any{"timeout": {int64(30)}},
+				},
+			},
+		}
+
+		err := conf.Get("", &TestStruct{})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !strings.Contains(err.Error(), `"30s"`) {
+			t.Fatalf("expected error to suggest a duration string, got %s", err)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package orale
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// Unmarshaler can be implemented by types that wish to decode their own
+// configuration values. UnmarshalConfig receives the raw values found at the
+// type's path along with the name of the source they were taken from. Values
+// are passed as loaded so flag and environment values will be strings, while
+// configuration file values keep the type given by the file's decoder.
+//
+// Unmarshaler takes precedence over encoding.TextUnmarshaler. Neither is
+// called if no value is found at the path.
+type Unmarshaler interface {
+	UnmarshalConfig(value []any, source string) error
+}
+
+var (
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// maybeUnmarshal decodes the value at the current path into the target if the
+// target implements Unmarshaler or encoding.TextUnmarshaler. The boolean
+// result is true if the target handled its own decoding.
+func maybeUnmarshal(l *Loader, currentPath string, targetRefVal reflect.Value, index int) (bool, error) {
+	if targetRefVal.Kind() == reflect.Ptr || targetRefVal.Kind() == reflect.Interface || !targetRefVal.CanAddr() {
+		return false, nil
+	}
+	targetPtrType := targetRefVal.Addr().Type()
+	implementsUnmarshaler := targetPtrType.Implements(unmarshalerType)
+	implementsTextUnmarshaler := targetPtrType.Implements(textUnmarshalerType)
+	if !implementsUnmarshaler && !implementsTextUnmarshaler {
+		return false, nil
+	}
+
+	value, source, err := resolveTargetValue(l, currentPath, targetRefVal.Type(), index)
+	if err != nil || len(value) == 0 {
+		return true, err
+	}
+
+	if implementsUnmarshaler {
+		unmarshaler := targetRefVal.Addr().Interface().(Unmarshaler)
+		if err := unmarshaler.UnmarshalConfig(value, source); err != nil {
+			return true, newConversionError(currentPath, source, targetRefVal.Type(), value, err)
+		}
+		return true, nil
+	}
+
+	text, err := convertToText(value[0])
+	if err != nil {
+		return true, newConversionError(currentPath, source, targetRefVal.Type(), value[0], err)
+	}
+	textUnmarshaler := targetRefVal.Addr().Interface().(encoding.TextUnmarshaler)
+	if err := textUnmarshaler.UnmarshalText(text); err != nil {
+		return true, newConversionError(currentPath, source, targetRefVal.Type(), value[0], err)
+	}
+	return true, nil
+}
+
+// resolveTargetValue resolves the values for a target that decodes itself.
+// If the index is not wholeValueIndex only the entry at the index is returned.
+func resolveTargetValue(l *Loader, currentPath string, targetType reflect.Type, index int) ([]any, string, error) {
+	if currentPath == "" {
+		return nil, "", fmt.Errorf("target path cannot be empty for %s", targetType)
+	}
+	value, source, err := resolveValue(l, currentPath)
+	if err != nil {
+		return nil, "", err
+	}
+	if index != wholeValueIndex {
+		if len(value) <= index {
+			return nil, "", nil
+		}
+		value = value[index : index+1]
+	}
+	return value, source, nil
+}
+
+// convertToText converts a loaded value into text suitable for
+// encoding.TextUnmarshaler. Strings are used as is while numbers and bools are
+// formatted.
+func convertToText(value any) ([]byte, error) {
+	switch val := value.(type) {
+	case string:
+		return []byte(val), nil
+	case int64, uint64, float64, bool:
+		return []byte(fmt.Sprint(val)), nil
+	}
+	return nil, newUnsupportedValueTypeError(value)
+}
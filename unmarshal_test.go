@@ -0,0 +1,176 @@
+package orale_test
+
+import (
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+
+	orale "github.com/RobertWHurst/orale"
+)
+
+type testMode int
+
+func (m *testMode) UnmarshalConfig(value []any, source string) error {
+	str, ok := value[0].(string)
+	if !ok {
+		return fmt.Errorf("expected a string from %s", source)
+	}
+	switch str {
+	case "fast":
+		*m = 1
+	case "slow":
+		*m = 2
+	default:
+		return fmt.Errorf("unknown mode %s", str)
+	}
+	return nil
+}
+
+type testPeers struct {
+	Values  []any
+	Sources []string
+}
+
+func (p *testPeers) UnmarshalConfig(value []any, source string) error {
+	p.Values = value
+	p.Sources = append(p.Sources, source)
+	return nil
+}
+
+func TestGetUnmarshaler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should decode types implementing encoding.TextUnmarshaler", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Ip       net.IP         `config:"ip"`
+			Addr     netip.Addr     `config:"addr"`
+			Big      *big.Int       `config:"big"`
+			Level    slog.Level     `config:"level"`
+			Allowed  []netip.Addr   `config:"allowed"`
+			Fallback netip.AddrPort `config:"fallback"`
+		}
+
+		conf := &orale.Loader{
+			FlagValues: map[string][]any{
+				"allowed": {"10.0.0.1", "10.0.0.2"},
+			},
+			EnvironmentValues: map[string][]any{
+				"ip":   {"192.168.0.1"},
+				"addr": {"::1"},
+			},
+			ConfigurationFiles: []*orale.File{
+				{
+					Path: "path/to/file.toml",
+					Values: map[string][]any{
+						"big":   {int64(12345)},
+						"level": {"warn"},
+					},
+				},
+			},
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if !testStruct.Ip.Equal(net.ParseIP("192.168.0.1")) {
+			t.Fatalf("expected Ip to be 192.168.0.1, got %s", testStruct.Ip)
+		}
+		if testStruct.Addr != netip.MustParseAddr("::1") {
+			t.Fatalf("expected Addr to be ::1, got %s", testStruct.Addr)
+		}
+		if testStruct.Big == nil || testStruct.Big.Int64() != 12345 {
+			t.Fatalf("expected Big to be 12345, got %s", testStruct.Big)
+		}
+		if testStruct.Level != slog.LevelWarn {
+			t.Fatalf("expected Level to be WARN, got %s", testStruct.Level)
+		}
+		if len(testStruct.Allowed) != 2 {
+			t.Fatalf("expected Allowed to have 2 values, got %d", len(testStruct.Allowed))
+		}
+		if testStruct.Allowed[1] != netip.MustParseAddr("10.0.0.2") {
+			t.Fatalf("expected Allowed[1] to be 10.0.0.2, got %s", testStruct.Allowed[1])
+		}
+		if testStruct.Fallback.IsValid() {
+			t.Fatalf("expected Fallback to be left unset, got %s", testStruct.Fallback)
+		}
+	})
+
+	t.Run("should return an error when UnmarshalText fails", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Addr netip.Addr `config:"addr"`
+		}
+
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{
+				"addr": {"not-an-ip"},
+			},
+		}
+
+		err := conf.Get("", &TestStruct{})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !strings.Contains(err.Error(), "addr") || !strings.Contains(err.Error(), "environment") {
+			t.Fatalf("expected error to name the path and source, got %s", err)
+		}
+	})
+
+	t.Run("should decode types implementing orale.Unmarshaler", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Mode  testMode  `config:"mode"`
+			Peers testPeers `config:"peers"`
+		}
+
+		conf := &orale.Loader{
+			FlagValues: map[string][]any{
+				"mode":  {"slow"},
+				"peers": {"a", "b"},
+			},
+		}
+
+		testStruct := TestStruct{}
+		if err := conf.Get("", &testStruct); err != nil {
+			t.Fatal(err)
+		}
+
+		if testStruct.Mode != 2 {
+			t.Fatalf("expected Mode to be 2, got %d", testStruct.Mode)
+		}
+		if len(testStruct.Peers.Values) != 2 {
+			t.Fatalf("expected Peers to receive 2 values, got %d", len(testStruct.Peers.Values))
+		}
+		if len(testStruct.Peers.Sources) != 1 || testStruct.Peers.Sources[0] != "flags" {
+			t.Fatalf("expected Peers to be called once with flags, got %v", testStruct.Peers.Sources)
+		}
+	})
+
+	t.Run("should return an error when UnmarshalConfig fails", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Mode testMode `config:"mode"`
+		}
+
+		conf := &orale.Loader{
+			FlagValues: map[string][]any{
+				"mode": {"medium"},
+			},
+		}
+
+		if err := conf.Get("", &TestStruct{}); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
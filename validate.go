@@ -0,0 +1,347 @@
+package orale
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError is returned by Get when values fail the rules given in
+// `validate` struct tags. It lists every failed rule at once.
+type ValidationError struct {
+	FieldErrors []FieldError
+}
+
+// FieldError describes a value that failed a validation rule.
+type FieldError struct {
+	// Path is the config path of the value.
+	Path string
+	// Source is the name of the source the value came from.
+	Source string
+	// Rule is the rule that failed, for example max=65535.
+	Rule string
+	// Message describes why the value failed the rule.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	message := "invalid configuration values:"
+	for _, fieldErr := range e.FieldErrors {
+		message += fmt.Sprintf("\n  %s from %s %s (%s)", fieldErr.Path, fieldErr.Source, fieldErr.Message, fieldErr.Rule)
+	}
+	return message
+}
+
+// validationRule is a single rule from a `validate` tag.
+type validationRule struct {
+	name  string
+	param string
+}
+
+func (r validationRule) String() string {
+	if r.param == "" {
+		return r.name
+	}
+	return r.name + "=" + r.param
+}
+
+// parseValidateTag splits a `validate` tag into its rules. Rules are comma
+// separated. As regular expressions may contain commas the pattern rule
+// consumes the remainder of the tag and so must be given last.
+func parseValidateTag(tag string) []validationRule {
+	rules := []validationRule{}
+	for tag != "" {
+		var chunk string
+		if strings.HasPrefix(tag, "pattern=") {
+			chunk, tag = tag, ""
+		} else if commaIndex := strings.IndexByte(tag, ','); commaIndex != -1 {
+			chunk, tag = tag[:commaIndex], tag[commaIndex+1:]
+		} else {
+			chunk, tag = tag, ""
+		}
+		chunk = strings.TrimSpace(chunk)
+		if chunk == "" {
+			continue
+		}
+		name, param, _ := strings.Cut(chunk, "=")
+		rules = append(rules, validationRule{name: name, param: param})
+	}
+	return rules
+}
+
+// validate walks the target after it has been populated and checks each field
+// against the rules in its `validate` tag. Supported rules are:
+//
+//   - omitempty skips the remaining rules if the value is its zero value
+//   - min and max compare numbers, or the length of strings, slices and maps
+//   - len requires a string, slice or map to have an exact length
+//   - oneof requires the value to be one of a space separated list
+//   - pattern requires a string to match a regular expression
+//   - eqfield, nefield, gtfield, gtefield, ltfield and ltefield compare the
+//     value against another field of the same struct by its Go field name
+//
+// Durations may use duration strings as parameters, for example min=1s.
+func validate(l *Loader, currentPath string, targetRefVal reflect.Value) error {
+	fieldErrs := []FieldError{}
+	var ruleErr error
+	walkFields(currentPath, targetRefVal, func(fieldPath string, field reflect.StructField, fieldRefVal, structRefVal reflect.Value) bool {
+		validateTag, ok := field.Tag.Lookup("validate")
+		if !ok || ruleErr != nil {
+			return ruleErr == nil
+		}
+		for fieldRefVal.Kind() == reflect.Ptr {
+			if fieldRefVal.IsNil() {
+				return true
+			}
+			fieldRefVal = fieldRefVal.Elem()
+		}
+		if innerRefVal, ok := secretInnerValue(fieldRefVal); ok {
+			fieldRefVal = innerRefVal
+		}
+
+		for _, rule := range parseValidateTag(validateTag) {
+			if rule.name == "omitempty" {
+				if fieldRefVal.IsZero() {
+					break
+				}
+				continue
+			}
+			message, err := applyValidationRule(rule, fieldRefVal, structRefVal)
+			if err != nil {
+				ruleErr = fmt.Errorf("invalid validation rule %s on field %s at path %s: %w", rule, field.Name, fieldPath, err)
+				return false
+			}
+			if message != "" {
+				fieldErrs = append(fieldErrs, FieldError{
+					Path:    fieldPath,
+					Source:  resolveSource(l, fieldPath, field),
+					Rule:    rule.String(),
+					Message: message,
+				})
+			}
+		}
+		return true
+	})
+	if ruleErr != nil {
+		return ruleErr
+	}
+	if len(fieldErrs) != 0 {
+		return &ValidationError{FieldErrors: fieldErrs}
+	}
+	return nil
+}
+
+// applyValidationRule checks a value against a rule. It returns a message
+// describing the failure, or an empty string if the value passes. An error is
+// returned if the rule itself is invalid.
+func applyValidationRule(rule validationRule, refVal, structRefVal reflect.Value) (string, error) {
+	switch rule.name {
+	case "min", "max":
+		cmp, isLen, err := compareValueToParam(refVal, rule.param)
+		if err != nil {
+			return "", err
+		}
+		subject := "must be"
+		if isLen {
+			subject = "must have a length of"
+		}
+		if rule.name == "min" && cmp < 0 {
+			return fmt.Sprintf("%s at least %s", subject, rule.param), nil
+		}
+		if rule.name == "max" && cmp > 0 {
+			return fmt.Sprintf("%s at most %s", subject, rule.param), nil
+		}
+
+	case "len":
+		if !hasLen(refVal) {
+			return "", fmt.Errorf("len cannot be used with %s", refVal.Type())
+		}
+		expectedLen, err := strconv.Atoi(rule.param)
+		if err != nil {
+			return "", err
+		}
+		if valueLen(refVal) != expectedLen {
+			return fmt.Sprintf("must have a length of %d", expectedLen), nil
+		}
+
+	case "oneof":
+		options := strings.Fields(rule.param)
+		str := fmt.Sprint(refVal.Interface())
+		for _, option := range options {
+			if str == option {
+				return "", nil
+			}
+		}
+		return fmt.Sprintf("must be one of %s", strings.Join(options, ", ")), nil
+
+	case "pattern":
+		if refVal.Kind() != reflect.String {
+			return "", fmt.Errorf("pattern cannot be used with %s", refVal.Type())
+		}
+		pattern, err := regexp.Compile(rule.param)
+		if err != nil {
+			return "", err
+		}
+		if !pattern.MatchString(refVal.String()) {
+			return fmt.Sprintf("must match the pattern %s", rule.param), nil
+		}
+
+	case "eqfield", "nefield", "gtfield", "gtefield", "ltfield", "ltefield":
+		otherRefVal := structRefVal.FieldByName(rule.param)
+		if !otherRefVal.IsValid() {
+			return "", fmt.Errorf("no field named %s", rule.param)
+		}
+		for otherRefVal.Kind() == reflect.Ptr {
+			if otherRefVal.IsNil() {
+				return "", nil
+			}
+			otherRefVal = otherRefVal.Elem()
+		}
+		cmp, err := compareValues(refVal, otherRefVal)
+		if err != nil {
+			return "", err
+		}
+		otherPath := parseConfigTag(mustFieldByName(structRefVal.Type(), rule.param)).name
+		switch {
+		case rule.name == "eqfield" && cmp != 0:
+			return fmt.Sprintf("must be equal to %s", otherPath), nil
+		case rule.name == "nefield" && cmp == 0:
+			return fmt.Sprintf("must not be equal to %s", otherPath), nil
+		case rule.name == "gtfield" && cmp <= 0:
+			return fmt.Sprintf("must be greater than %s", otherPath), nil
+		case rule.name == "gtefield" && cmp < 0:
+			return fmt.Sprintf("must be greater than or equal to %s", otherPath), nil
+		case rule.name == "ltfield" && cmp >= 0:
+			return fmt.Sprintf("must be less than %s", otherPath), nil
+		case rule.name == "ltefield" && cmp > 0:
+			return fmt.Sprintf("must be less than or equal to %s", otherPath), nil
+		}
+
+	default:
+		return "", fmt.Errorf("unknown rule")
+	}
+	return "", nil
+}
+
+// compareValueToParam compares a value against a rule parameter. Strings,
+// slices, arrays and maps are compared by length, in which case the boolean
+// result is true.
+func compareValueToParam(refVal reflect.Value, param string) (int, bool, error) {
+	if hasLen(refVal) {
+		paramLen, err := strconv.Atoi(param)
+		if err != nil {
+			return 0, true, err
+		}
+		return compareOrdered(valueLen(refVal), paramLen), true, nil
+	}
+
+	if refVal.Type() == durationType {
+		paramDuration, err := time.ParseDuration(param)
+		if err != nil {
+			return 0, false, err
+		}
+		return compareOrdered(time.Duration(refVal.Int()), paramDuration), false, nil
+	}
+
+	switch refVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		paramInt, err := strconv.ParseInt(param, 0, 64)
+		if err != nil {
+			return 0, false, err
+		}
+		return compareOrdered(refVal.Int(), paramInt), false, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		paramUint, err := strconv.ParseUint(param, 0, 64)
+		if err != nil {
+			return 0, false, err
+		}
+		return compareOrdered(refVal.Uint(), paramUint), false, nil
+	case reflect.Float32, reflect.Float64:
+		paramFloat, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return 0, false, err
+		}
+		return compareOrdered(refVal.Float(), paramFloat), false, nil
+	}
+	return 0, false, fmt.Errorf("cannot be used with %s", refVal.Type())
+}
+
+// compareValues compares two field values of the same kind.
+func compareValues(refVal, otherRefVal reflect.Value) (int, error) {
+	if refVal.Type() == timeType && otherRefVal.Type() == timeType {
+		return refVal.Interface().(time.Time).Compare(otherRefVal.Interface().(time.Time)), nil
+	}
+
+	switch refVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch otherRefVal.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return compareOrdered(refVal.Int(), otherRefVal.Int()), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch otherRefVal.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return compareOrdered(refVal.Uint(), otherRefVal.Uint()), nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch otherRefVal.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return compareOrdered(refVal.Float(), otherRefVal.Float()), nil
+		}
+	case reflect.String:
+		if otherRefVal.Kind() == reflect.String {
+			return strings.Compare(refVal.String(), otherRefVal.String()), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %s with %s", refVal.Type(), otherRefVal.Type())
+}
+
+func compareOrdered[T int | int64 | uint64 | float64 | time.Duration](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func hasLen(refVal reflect.Value) bool {
+	switch refVal.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+func valueLen(refVal reflect.Value) int {
+	if refVal.Kind() == reflect.String {
+		return utf8.RuneCountInString(refVal.String())
+	}
+	return refVal.Len()
+}
+
+func mustFieldByName(structType reflect.Type, name string) reflect.StructField {
+	field, _ := structType.FieldByName(name)
+	return field
+}
+
+// resolveSource returns the name of the source providing the value at or
+// beneath the target path. If no source provides it the value came from a
+// default tag or was left as given to Get.
+func resolveSource(l *Loader, targetPath string, field reflect.StructField) string {
+	if _, source, _ := resolveValue(l, targetPath); source != "" {
+		return source
+	}
+	if source := findPathSource(l, targetPath); source != nil {
+		return source.Name()
+	}
+	if _, ok := field.Tag.Lookup("default"); ok {
+		return defaultSourceName
+	}
+	return "initial value"
+}
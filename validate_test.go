@@ -0,0 +1,158 @@
+package orale_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	orale "github.com/RobertWHurst/orale"
+)
+
+func TestGetValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should accept values that pass their validation rules", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Port     int           `config:"port" validate:"min=1,max=65535"`
+			Level    string        `config:"level" validate:"oneof=debug info warn"`
+			Name     string        `config:"name" validate:"len=5,pattern=^[a-z]+$"`
+			Hosts    []string      `config:"hosts" validate:"min=1"`
+			Timeout  time.Duration `config:"timeout" validate:"min=1s,max=1m"`
+			MinConns int           `config:"min_conns"`
+			MaxConns int           `config:"max_conns" validate:"gtefield=MinConns"`
+			Proxy    string        `config:"proxy" validate:"omitempty,pattern=^http"`
+		}
+
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{
+				"port":      {"8080"},
+				"level":     {"info"},
+				"name":      {"orale"},
+				"hosts":     {"a"},
+				"timeout":   {"30s"},
+				"min_conns": {"2"},
+				"max_conns": {"2"},
+			},
+		}
+
+		if err := conf.Get("", &TestStruct{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("should collect every failed rule along with its path and source", func(t *testing.T) {
+		t.Parallel()
+
+		type Server struct {
+			Port int `config:"port" validate:"min=1,max=65535"`
+		}
+		type TestStruct struct {
+			Server   Server `config:"server"`
+			Level    string `config:"level" validate:"oneof=debug info warn"`
+			Name     string `config:"name" validate:"pattern=^[a-z]+$"`
+			MinConns int    `config:"min_conns"`
+			MaxConns int    `config:"max_conns" validate:"gtfield=MinConns"`
+		}
+
+		conf := &orale.Loader{
+			FlagValues: map[string][]any{
+				"server.port": {"70000"},
+			},
+			EnvironmentValues: map[string][]any{
+				"level": {"verbose"},
+			},
+			ConfigurationFiles: []*orale.File{
+				{
+					Path: "path/to/file.toml",
+					Values: map[string][]any{
+						"name":      {"Orale"},
+						"min_conns": {int64(5)},
+						"max_conns": {int64(1)},
+					},
+				},
+			},
+		}
+
+		err := conf.Get("", &TestStruct{})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		var validationErr *orale.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected a ValidationError, got %T", err)
+		}
+		if len(validationErr.FieldErrors) != 4 {
+			t.Fatalf("expected 4 field errors, got %d: %s", len(validationErr.FieldErrors), err)
+		}
+
+		fieldErr := validationErr.FieldErrors[0]
+		if fieldErr.Path != "server.port" || fieldErr.Source != "flags" || fieldErr.Rule != "max=65535" {
+			t.Fatalf("expected server.port from flags to fail max=65535, got %+v", fieldErr)
+		}
+		if fieldErr.Message != "must be at most 65535" {
+			t.Fatalf("expected server.port to be described as must be at most 65535, got %s", fieldErr.Message)
+		}
+		fieldErr = validationErr.FieldErrors[1]
+		if fieldErr.Path != "level" || fieldErr.Source != "environment" {
+			t.Fatalf("expected level from environment to fail, got %+v", fieldErr)
+		}
+		fieldErr = validationErr.FieldErrors[2]
+		if fieldErr.Path != "name" || fieldErr.Source != "path/to/file.toml" {
+			t.Fatalf("expected name from path/to/file.toml to fail, got %+v", fieldErr)
+		}
+		fieldErr = validationErr.FieldErrors[3]
+		if fieldErr.Path != "max_conns" || !strings.Contains(fieldErr.Message, "min_conns") {
+			t.Fatalf("expected max_conns to fail against min_conns, got %+v", fieldErr)
+		}
+	})
+
+	t.Run("should describe length rules by the length", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Hosts []string `config:"hosts" validate:"min=2"`
+			Name  string   `config:"name" validate:"max=3"`
+		}
+
+		conf := &orale.Loader{
+			EnvironmentValues: map[string][]any{
+				"hosts": {"a"},
+				"name":  {"orale"},
+			},
+		}
+
+		err := conf.Get("", &TestStruct{})
+		var validationErr *orale.ValidationError
+		if !errors.As(err, &validationErr) || len(validationErr.FieldErrors) != 2 {
+			t.Fatalf("expected a ValidationError with 2 field errors, got %v", err)
+		}
+		if message := validationErr.FieldErrors[0].Message; message != "must have a length of at least 2" {
+			t.Fatalf("expected hosts to be described as must have a length of at least 2, got %s", message)
+		}
+		if message := validationErr.FieldErrors[1].Message; message != "must have a length of at most 3" {
+			t.Fatalf("expected name to be described as must have a length of at most 3, got %s", message)
+		}
+	})
+
+	t.Run("should return an error for invalid rules", func(t *testing.T) {
+		t.Parallel()
+
+		type TestStruct struct {
+			Port int `config:"port" validate:"between=1 2"`
+		}
+
+		conf := &orale.Loader{}
+		err := conf.Get("", &TestStruct{})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		var validationErr *orale.ValidationError
+		if errors.As(err, &validationErr) {
+			t.Fatal("expected an invalid rule error rather than a ValidationError")
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package orale
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// walkFields calls fn for every exported struct field reachable from the
+// target, along with the field's config path and the struct value holding the
+// field. Pointers, secrets, slices, arrays and
+// string keyed maps are followed so nested structs are visited as well. If fn
+// returns false the field's value is not walked into.
+func walkFields(currentPath string, targetRefVal reflect.Value, fn func(fieldPath string, field reflect.StructField, fieldRefVal, structRefVal reflect.Value) bool) {
+	switch targetRefVal.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !targetRefVal.IsNil() {
+			walkFields(currentPath, targetRefVal.Elem(), fn)
+		}
+
+	case reflect.Struct:
+		if targetRefVal.Type() == timeType {
+			return
+		}
+		if innerRefVal, ok := secretInnerValue(targetRefVal); ok {
+			walkFields(currentPath, innerRefVal, fn)
+			return
+		}
+		for i := 0; i < targetRefVal.NumField(); i += 1 {
+			field := targetRefVal.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := joinPath(currentPath, parseConfigTag(field).name)
+			if fn(fieldPath, field, targetRefVal.Field(i), targetRefVal) {
+				walkFields(fieldPath, targetRefVal.Field(i), fn)
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < targetRefVal.Len(); i += 1 {
+			walkFields(fmt.Sprintf("%s[%d]", currentPath, i), targetRefVal.Index(i), fn)
+		}
+
+	case reflect.Map:
+		if targetRefVal.Type().Key().Kind() != reflect.String {
+			return
+		}
+		keys := targetRefVal.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, key := range keys {
+			walkFields(joinPath(currentPath, escapePathKey(key.String())), targetRefVal.MapIndex(key), fn)
+		}
+	}
+}